@@ -0,0 +1,282 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package rcon
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	liblog "github.com/Aton-Kish/gorcon/log"
+	"github.com/Aton-Kish/gorcon/protocol"
+)
+
+// Result is the outcome of an asynchronous command.
+type Result struct {
+	Command  string
+	Response string
+	Err      error
+}
+
+// Batch is a list of commands sent together. On connections without
+// middlewares the commands are pipelined: every request is written up front
+// and the responses are matched back by request ID.
+type Batch struct {
+	commands []string
+}
+
+func NewBatch(commands ...string) *Batch {
+	return &Batch{commands: commands}
+}
+
+func (b *Batch) Add(command string) *Batch {
+	b.commands = append(b.commands, command)
+	return b
+}
+
+func (b *Batch) Len() int {
+	return len(b.commands)
+}
+
+// Run sends the commands over c and returns their results in order.
+func (b *Batch) Run(ctx context.Context, c Client) []Result {
+	if p, ok := c.(batcher); ok {
+		return p.commandBatch(ctx, b.commands)
+	}
+
+	results := make([]Result, len(b.commands))
+	for i, command := range b.commands {
+		res, err := c.CommandContext(ctx, command)
+		results[i] = Result{Command: command, Response: res, Err: err}
+	}
+
+	return results
+}
+
+type batcher interface {
+	commandBatch(ctx context.Context, commands []string) []Result
+}
+
+// AsyncCommander is implemented by clients that send commands in the
+// background, such as connections returned by Dial and KeepaliveClient.
+type AsyncCommander interface {
+	// CommandAsync sends the command in the background. The returned channel
+	// receives exactly one Result.
+	CommandAsync(command string) <-chan Result
+}
+
+var (
+	_ AsyncCommander = (*rcon)(nil)
+	_ AsyncCommander = (*KeepaliveClient)(nil)
+)
+
+func (c *rcon) CommandAsync(command string) <-chan Result {
+	ch := make(chan Result, 1)
+
+	go func() {
+		res, err := c.CommandContext(context.Background(), command)
+		ch <- Result{Command: command, Response: res, Err: err}
+	}()
+
+	return ch
+}
+
+func (c *rcon) commandBatch(ctx context.Context, commands []string) []Result {
+	if c.hasMiddleware {
+		// NOTE: every command must go through the middleware chain
+		results := make([]Result, len(commands))
+		for i, command := range commands {
+			res, err := c.CommandContext(ctx, command)
+			results[i] = Result{Command: command, Response: res, Err: err}
+		}
+
+		return results
+	}
+
+	// NOTE: invalid commands are never sent
+	start := time.Now()
+	results := make([]Result, len(commands))
+	valid := make([]string, 0, len(commands))
+	for i, command := range commands {
+		if err := c.validate(command); err != nil {
+			c.stats.failures.Add(1)
+			err = &RCONError{Op: "command", Err: err}
+			results[i] = Result{Command: command, Err: err}
+			c.hooks.CommandDone(c.addr, time.Since(start), 0, 0, err)
+			c.getLogger(ctx).Log(liblog.LevelError, "failed to command", liblog.String("func", getFuncName()), liblog.String("command", c.redact(command)), liblog.Err(err))
+			continue
+		}
+
+		valid = append(valid, command)
+	}
+
+	if len(valid) == len(commands) {
+		return c.pipeline(ctx, commands)
+	}
+
+	piped := c.pipeline(ctx, valid)
+	for i := range results {
+		if results[i].Err == nil {
+			results[i], piped = piped[0], piped[1:]
+		}
+	}
+
+	return results
+}
+
+// pipeline writes every command followed by a dummy request with the same ID,
+// and reads the responses concurrently. A response is complete when the
+// server answers the dummy request.
+func (c *rcon) pipeline(ctx context.Context, commands []string) []Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	logger := c.getLogger(ctx)
+
+	results := make([]Result, len(commands))
+	index := make(map[int32]int, len(commands))
+	packets := make([]*protocol.Packet, 0, 2*len(commands))
+	for i, command := range commands {
+		results[i].Command = command
+
+		id := rand.Int31()
+		for _, ok := index[id]; ok || id == unauthorizedRequestID; _, ok = index[id] {
+			id = rand.Int31()
+		}
+		index[id] = i
+
+		packets = append(
+			packets,
+			protocol.NewPacket(id, protocol.CommandRequestType, []byte(command)),
+			protocol.NewPacket(id, protocol.DummyRequestType, []byte{}),
+		)
+	}
+
+	if len(commands) == 0 {
+		return results
+	}
+
+	start := time.Now()
+
+	if err := ctx.Err(); err != nil {
+		return c.failBatch(results, make([]bool, len(results)), start, err)
+	}
+
+	stop := c.watch(ctx)
+
+	writeErr := make(chan error, 1)
+	go func() {
+		for _, p := range packets {
+			if err := c.encode(ctx, p); err != nil {
+				writeErr <- err
+				// NOTE: unblock the reader
+				_ = c.Conn.SetReadDeadline(time.Unix(1, 0))
+				return
+			}
+		}
+
+		writeErr <- nil
+	}()
+
+	payloads := make([][]byte, len(commands))
+	seen := make([]bool, len(commands))
+	done := make([]bool, len(commands))
+	continuations := make([]int, len(commands))
+
+	var err error
+	for remaining := len(commands); remaining > 0; {
+		p := new(protocol.Packet)
+		if err = c.decode(ctx, p, false); err != nil {
+			break
+		}
+
+		i, ok := index[p.RequestId]
+		if !ok || done[i] {
+			err = fmt.Errorf("unexpected request id %d", p.RequestId)
+			break
+		}
+
+		if string(p.Payload) == "Unknown request 64" {
+			// NOTE: termination
+			c.stats.commands.Add(1)
+			c.hooks.CommandDone(c.addr, time.Since(start), len(payloads[i]), continuations[i], nil)
+			results[i].Response = string(payloads[i])
+			done[i] = true
+			remaining--
+			continue
+		}
+
+		if seen[i] {
+			continuations[i]++
+		}
+		seen[i] = true
+		payloads[i] = append(payloads[i], p.Payload...)
+	}
+
+	if err == nil {
+		err = <-writeErr
+	} else {
+		select {
+		case wErr := <-writeErr:
+			// NOTE: the reader may have been stopped by the writer
+			if wErr != nil {
+				err = wErr
+			}
+		default:
+			// NOTE: the server is no longer read, so a large batch can block
+			// the writer forever
+			c.breakConn()
+			<-writeErr
+		}
+	}
+	stop(err)
+
+	if err != nil {
+		// NOTE: the responses of the remaining commands are still in flight,
+		// and the writer may have moved the read deadline
+		c.breakConn()
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+
+		logger.Log(liblog.LevelError, "failed to pipeline", liblog.String("func", getFuncName()), liblog.Err(err))
+		return c.failBatch(results, done, start, err)
+	}
+
+	return results
+}
+
+// failBatch sets err on every result that is not done.
+func (c *rcon) failBatch(results []Result, done []bool, start time.Time, err error) []Result {
+	for i := range results {
+		if done[i] {
+			continue
+		}
+
+		c.stats.failures.Add(1)
+		results[i].Err = &RCONError{Op: "command", Err: err}
+		c.hooks.CommandDone(c.addr, time.Since(start), 0, 0, results[i].Err)
+	}
+
+	return results
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package rcon
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aton-Kish/gorcon/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+type latencyHooks struct {
+	NopHooks
+
+	mu        sync.Mutex
+	latencies []time.Duration
+	errs      []error
+}
+
+func (h *latencyHooks) CommandDone(addr string, latency time.Duration, size int, continuations int, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.latencies = append(h.latencies, latency)
+	h.errs = append(h.errs, err)
+}
+
+func TestBatch_Run(t *testing.T) {
+	expected := []Result{
+		{Command: "/seed", Response: "/seed"},
+		{Command: "/help", Response: strings.Repeat("a", maxResponsePayloadSize) + "b"},
+		{Command: "/list", Response: "/list"},
+	}
+
+	t.Run("positive case: pipeline", func(t *testing.T) {
+		s := newMockServer(t, 0)
+		defer s.close()
+
+		conn, err := Dial(s.addr(), mockPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		c := AsClient(conn)
+		results := NewBatch("/seed", "/help").Add("/list").Run(context.Background(), c)
+
+		assert.Equal(t, expected, results)
+		assert.Equal(t, Stats{Commands: 3, PacketsSent: 7, PacketsReceived: 8}, c.Stats())
+	})
+
+	t.Run("positive case: middleware", func(t *testing.T) {
+		s := newMockServer(t, 0)
+		defer s.close()
+
+		calls := 0
+		count := func(next CommandFunc) CommandFunc {
+			return func(ctx context.Context, command string) (string, error) {
+				calls++
+				return next(ctx, command)
+			}
+		}
+
+		conn, err := Dial(s.addr(), mockPassword, WithMiddleware(count))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		results := NewBatch("/seed", "/help", "/list").Run(context.Background(), AsClient(conn))
+
+		assert.Equal(t, expected, results)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("positive case: other client", func(t *testing.T) {
+		srv, clt := net.Pipe()
+		defer srv.Close()
+		defer clt.Close()
+
+		m := &mockRCON{Conn: clt, responses: map[string]string{"/seed": "Seed: [1]"}}
+		results := NewBatch("/seed", "/unknown").Run(context.Background(), AsClient(m))
+
+		assert.Len(t, results, 2)
+		assert.Equal(t, Result{Command: "/seed", Response: "Seed: [1]"}, results[0])
+		assert.Error(t, results[1].Err)
+	})
+
+	t.Run("positive case: empty", func(t *testing.T) {
+		_, clt := pipe()
+		defer clt.Close()
+
+		assert.Equal(t, []Result{}, NewBatch().Run(context.Background(), clt))
+	})
+
+	t.Run("negative case: canceled", func(t *testing.T) {
+		srv, clt := pipe()
+		defer srv.Close()
+		defer clt.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results := NewBatch("/seed", "/list").Run(ctx, clt)
+
+		assert.Len(t, results, 2)
+		for _, r := range results {
+			assert.IsType(t, &RCONError{}, r.Err)
+			assert.ErrorIs(t, r.Err, context.Canceled)
+		}
+		assert.Equal(t, Stats{Failures: 2}, clt.Stats())
+	})
+
+	t.Run("negative case: reader stops early", func(t *testing.T) {
+		srv, clt := pipe()
+		defer srv.Close()
+		defer clt.Close()
+
+		go func() {
+			req, err := srv.ReadPacket()
+			if err != nil {
+				return
+			}
+
+			// NOTE: answer with an unknown ID, then stop reading
+			_ = srv.SendPacket(protocol.NewPacket(req.RequestId+1, protocol.CommandResponseType, []byte("response")))
+		}()
+
+		done := make(chan []Result, 1)
+		go func() {
+			done <- NewBatch("/seed", "/help", "/list").Run(context.Background(), clt)
+		}()
+
+		select {
+		case results := <-done:
+			assert.Len(t, results, 3)
+			for _, r := range results {
+				assert.IsType(t, &RCONError{}, r.Err)
+				assert.ErrorContains(t, r.Err, "unexpected request id")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("batch blocked by the writer")
+		}
+
+		_, err := clt.Command("/seed")
+		assert.ErrorIs(t, err, ErrBroken)
+	})
+
+	t.Run("negative case: hooks", func(t *testing.T) {
+		h := new(latencyHooks)
+
+		srv, clt := pipe(WithHooks(h), WithMaxCommandSize(8))
+		defer clt.Close()
+		srv.Close()
+
+		results := NewBatch("/seed", "/too long command").Run(context.Background(), clt)
+
+		assert.Len(t, results, 2)
+		assert.ErrorIs(t, results[1].Err, ErrPayloadTooLarge)
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		// NOTE: the invalid command is reported, and failures carry their latency
+		assert.Len(t, h.errs, 2)
+		for _, latency := range h.latencies {
+			assert.Greater(t, latency, time.Duration(0))
+		}
+	})
+
+	t.Run("negative case: connection closed", func(t *testing.T) {
+		srv, clt := pipe()
+		defer clt.Close()
+		srv.Close()
+
+		results := NewBatch("/seed").Run(context.Background(), clt)
+
+		assert.Len(t, results, 1)
+		assert.IsType(t, &RCONError{}, results[0].Err)
+	})
+}
+
+func Test_rcon_CommandAsync(t *testing.T) {
+	s := newMockServer(t, 0)
+	defer s.close()
+
+	conn, err := Dial(s.addr(), mockPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	a, ok := conn.(AsyncCommander)
+	if !ok {
+		t.Fatal("connection does not implement AsyncCommander")
+	}
+
+	chs := []<-chan Result{
+		a.CommandAsync("/seed"),
+		a.CommandAsync("/help"),
+		a.CommandAsync("/list"),
+	}
+
+	for _, ch := range chs {
+		r := <-ch
+		assert.NoError(t, r.Err)
+		if r.Command == "/help" {
+			assert.Len(t, r.Response, maxResponsePayloadSize+1)
+		} else {
+			assert.Equal(t, r.Command, r.Response)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package rcon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrBreakerOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a Breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every call through.
+	BreakerClosed = BreakerState(iota)
+	// BreakerOpen fails every call fast with ErrBreakerOpen.
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe through to decide whether to close
+	// or reopen.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", int(s))
+	}
+}
+
+// BreakerConfig configures a Breaker.
+type BreakerConfig struct {
+	// Threshold is the number of consecutive RCONErrors that trips the
+	// breaker. It defaults to 5.
+	Threshold int
+	// Cooldown is how long the breaker stays open before probing. It
+	// defaults to 30 seconds.
+	Cooldown time.Duration
+	// OnStateChange, if set, is called on every state change. It must not
+	// call back into the breaker.
+	OnStateChange func(from BreakerState, to BreakerState)
+}
+
+// Breaker is a circuit breaker for a connection or a fleet of connections.
+// Only RCONErrors count as failures, except those caused by a canceled
+// context and those raised before the command was sent, such as a deadline
+// hit while waiting for a Limiter.
+type Breaker struct {
+	cfg BreakerConfig
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewBreaker(cfg BreakerConfig) *Breaker {
+	if cfg.Threshold < 1 {
+		cfg.Threshold = 5
+	}
+
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 30 * time.Second
+	}
+
+	return &Breaker{cfg: cfg}
+}
+
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Do calls f unless the breaker is open, and records its result.
+func (b *Breaker) Do(f func() error) error {
+	probe, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	err = f()
+	b.record(probe, err)
+
+	return err
+}
+
+// Middleware returns a Middleware that guards every command with the breaker.
+func (b *Breaker) Middleware() Middleware {
+	return func(next CommandFunc) CommandFunc {
+		return func(ctx context.Context, command string) (string, error) {
+			var res string
+			err := b.Do(func() error {
+				var err error
+				res, err = next(ctx, command)
+				return err
+			})
+
+			return res, err
+		}
+	}
+}
+
+// DialContext is like the package-level DialContext, but fails fast while the
+// breaker is open.
+func (b *Breaker) DialContext(ctx context.Context, addr string, password string, opts ...Option) (RCON, error) {
+	var c RCON
+	err := b.Do(func() error {
+		var err error
+		c, err = DialContext(ctx, addr, password, opts...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (b *Breaker) allow() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cfg.Cooldown {
+			return false, &RCONError{Op: "breaker", Err: ErrBreakerOpen}
+		}
+
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+
+		return true, nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return false, &RCONError{Op: "breaker", Err: ErrBreakerOpen}
+		}
+
+		b.probing = true
+
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func (b *Breaker) record(probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing = false
+	} else if b.state != BreakerClosed {
+		// NOTE: the call was let in before the breaker tripped, and only the
+		// probe decides when to leave open and half-open
+		return
+	}
+
+	var rerr *RCONError
+	switch {
+	case err == nil:
+		b.failures = 0
+		if probe {
+			b.setState(BreakerClosed)
+		}
+	case errors.As(err, &rerr) && !errors.Is(err, context.Canceled) && !isInvalidRequest(err) && !isUnsent(err):
+		b.failures++
+		if probe || b.failures >= b.cfg.Threshold {
+			b.openedAt = time.Now()
+			b.setState(BreakerOpen)
+		}
+	}
+}
+
+// setState changes the state. b.mu must be held.
+func (b *Breaker) setState(s BreakerState) {
+	from := b.state
+	b.state = s
+
+	if b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(from, s)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package rcon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	transitions := []string{}
+	b := NewBreaker(BreakerConfig{
+		Threshold: 2,
+		Cooldown:  20 * time.Millisecond,
+		OnStateChange: func(from BreakerState, to BreakerState) {
+			transitions = append(transitions, fmt.Sprintf("%s -> %s", from, to))
+		},
+	})
+
+	errFailed := &RCONError{Op: "command", Err: errors.New("timeout")}
+	calls := 0
+	fail := func() error { calls++; return errFailed }
+	succeed := func() error { calls++; return nil }
+
+	// NOTE: non-RCON errors and canceled contexts do not count
+	assert.Error(t, b.Do(func() error { return errors.New("blocked") }))
+	assert.Error(t, b.Do(func() error { return &RCONError{Op: "command", Err: context.Canceled} }))
+	assert.Equal(t, BreakerClosed, b.State())
+
+	assert.ErrorIs(t, b.Do(fail), errFailed)
+	assert.ErrorIs(t, b.Do(fail), errFailed)
+	assert.Equal(t, BreakerOpen, b.State())
+
+	// NOTE: fail fast while open
+	err := b.Do(succeed)
+	assert.ErrorIs(t, err, ErrBreakerOpen)
+	assert.IsType(t, &RCONError{}, err)
+	assert.Equal(t, 2, calls)
+
+	// NOTE: failed probe reopens
+	time.Sleep(30 * time.Millisecond)
+	assert.ErrorIs(t, b.Do(fail), errFailed)
+	assert.Equal(t, BreakerOpen, b.State())
+	assert.ErrorIs(t, b.Do(succeed), ErrBreakerOpen)
+
+	// NOTE: successful probe closes
+	time.Sleep(30 * time.Millisecond)
+	assert.NoError(t, b.Do(succeed))
+	assert.Equal(t, BreakerClosed, b.State())
+	assert.Equal(t, 4, calls)
+
+	expected := []string{
+		"closed -> open",
+		"open -> half-open",
+		"half-open -> open",
+		"open -> half-open",
+		"half-open -> closed",
+	}
+	assert.Equal(t, expected, transitions)
+}
+
+func TestBreaker_halfOpenSingleProbe(t *testing.T) {
+	b := NewBreaker(BreakerConfig{Threshold: 1, Cooldown: time.Millisecond})
+	assert.Error(t, b.Do(func() error { return &RCONError{Op: "command"} }))
+	time.Sleep(5 * time.Millisecond)
+
+	err := b.Do(func() error {
+		// NOTE: concurrent calls fail fast during the probe
+		assert.Equal(t, BreakerHalfOpen, b.State())
+		assert.ErrorIs(t, b.Do(func() error { return nil }), ErrBreakerOpen)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerClosed, b.State())
+}
+
+func TestBreaker_staleResult(t *testing.T) {
+	b := NewBreaker(BreakerConfig{Threshold: 2, Cooldown: time.Hour})
+	errFailed := &RCONError{Op: "command", Err: errors.New("timeout")}
+
+	err := b.Do(func() error {
+		// NOTE: the breaker trips while this call is in flight
+		assert.Error(t, b.Do(func() error { return errFailed }))
+		assert.Error(t, b.Do(func() error { return errFailed }))
+		assert.Equal(t, BreakerOpen, b.State())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerOpen, b.State())
+	assert.ErrorIs(t, b.Do(func() error { return nil }), ErrBreakerOpen)
+}
+
+func TestBreaker_unsent(t *testing.T) {
+	b := NewBreaker(BreakerConfig{Threshold: 1, Cooldown: time.Hour})
+	l := NewLimiter(1, 1)
+	assert.NoError(t, l.Wait(context.Background()))
+
+	srv, clt := pipe(WithMiddleware(b.Middleware(), l.Middleware()))
+	defer srv.Close()
+	defer clt.Close()
+
+	// NOTE: the deadline is hit while waiting for a token
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := clt.CommandContext(ctx, "request")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, BreakerClosed, b.State())
+
+	// NOTE: the deadline is hit before sending
+	_, err = clt.command(ctx, "request")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Error(t, b.Do(func() error { return err }))
+	assert.Equal(t, BreakerClosed, b.State())
+}
+
+func TestBreaker_Middleware(t *testing.T) {
+	b := NewBreaker(BreakerConfig{Threshold: 1, Cooldown: time.Hour})
+
+	srv, clt := pipe(WithMiddleware(b.Middleware()))
+	defer clt.Close()
+	srv.Close()
+
+	_, err := clt.Command("request")
+	assert.IsType(t, &RCONError{}, err)
+	assert.NotErrorIs(t, err, ErrBreakerOpen)
+
+	_, err = clt.Command("request")
+	assert.ErrorIs(t, err, ErrBreakerOpen)
+	assert.Equal(t, Stats{Failures: 1}, clt.Stats())
+}
+
+func TestBreaker_DialContext(t *testing.T) {
+	b := NewBreaker(BreakerConfig{Threshold: 1, Cooldown: time.Hour})
+
+	conn, err := b.DialContext(context.Background(), "localhost:50000", mockPassword)
+	assert.IsType(t, &RCONError{}, err)
+	assert.Nil(t, conn)
+
+	conn, err = b.DialContext(context.Background(), "localhost:50000", mockPassword)
+	assert.ErrorIs(t, err, ErrBreakerOpen)
+	assert.Nil(t, conn)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package rcon
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// Client is the connection-agnostic contract of an RCON client.
+//
+// Unlike RCON it does not expose the underlying net.Conn, so callers can not
+// corrupt the protocol stream, and pools, reconnecting clients, mocks or
+// alternative protocols can implement it as well.
+type Client interface {
+	Command(command string) (string, error)
+	CommandContext(ctx context.Context, command string) (string, error)
+	Close() error
+
+	LocalAddr() net.Addr
+	RemoteAddr() net.Addr
+
+	SetDeadline(t time.Time) error
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+
+	Stats() Stats
+}
+
+// Stats is a snapshot of client activity counters.
+type Stats struct {
+	Commands        uint64
+	Failures        uint64
+	PacketsSent     uint64
+	PacketsReceived uint64
+}
+
+type stats struct {
+	commands        atomic.Uint64
+	failures        atomic.Uint64
+	packetsSent     atomic.Uint64
+	packetsReceived atomic.Uint64
+}
+
+func (s *stats) snapshot() Stats {
+	return Stats{
+		Commands:        s.commands.Load(),
+		Failures:        s.failures.Load(),
+		PacketsSent:     s.packetsSent.Load(),
+		PacketsReceived: s.packetsReceived.Load(),
+	}
+}
+
+// AsClient adapts an RCON to the Client interface.
+//
+// Connections returned by Dial already implement Client and are returned as
+// is. Other implementations are wrapped, and only command counters are
+// recorded for them.
+func AsClient(r RCON) Client {
+	if c, ok := r.(Client); ok {
+		return c
+	}
+
+	return &clientAdapter{r: r}
+}
+
+type contextCommander interface {
+	CommandContext(ctx context.Context, command string) (string, error)
+}
+
+type clientAdapter struct {
+	r     RCON
+	stats stats
+}
+
+func (a *clientAdapter) Command(command string) (string, error) {
+	res, err := a.r.Command(command)
+	if err != nil {
+		a.stats.failures.Add(1)
+		return "", err
+	}
+
+	a.stats.commands.Add(1)
+
+	return res, nil
+}
+
+// CommandContext uses the CommandContext method of the wrapped RCON if it has
+// one. Otherwise ctx is only checked before the command is sent.
+func (a *clientAdapter) CommandContext(ctx context.Context, command string) (string, error) {
+	var (
+		res string
+		err error
+	)
+	if c, ok := a.r.(contextCommander); ok {
+		res, err = c.CommandContext(ctx, command)
+	} else if err = ctx.Err(); err != nil {
+		err = &RCONError{Op: "command", Err: &unsentError{err: err}}
+	} else {
+		res, err = a.r.Command(command)
+	}
+	if err != nil {
+		a.stats.failures.Add(1)
+		return "", err
+	}
+
+	a.stats.commands.Add(1)
+
+	return res, nil
+}
+
+func (a *clientAdapter) Close() error {
+	return a.r.Close()
+}
+
+func (a *clientAdapter) LocalAddr() net.Addr {
+	return a.r.LocalAddr()
+}
+
+func (a *clientAdapter) RemoteAddr() net.Addr {
+	return a.r.RemoteAddr()
+}
+
+func (a *clientAdapter) SetDeadline(t time.Time) error {
+	return a.r.SetDeadline(t)
+}
+
+func (a *clientAdapter) SetReadDeadline(t time.Time) error {
+	return a.r.SetReadDeadline(t)
+}
+
+func (a *clientAdapter) SetWriteDeadline(t time.Time) error {
+	return a.r.SetWriteDeadline(t)
+}
+
+func (a *clientAdapter) Stats() Stats {
+	return a.stats.snapshot()
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package rcon
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRCON struct {
+	net.Conn
+
+	responses map[string]string
+}
+
+func (m *mockRCON) Command(command string) (string, error) {
+	res, ok := m.responses[command]
+	if !ok {
+		return "", errors.New("unknown command")
+	}
+
+	return res, nil
+}
+
+func (m *mockRCON) CommandContext(ctx context.Context, command string) (string, error) {
+	return m.Command(command)
+}
+
+func TestAsClient(t *testing.T) {
+	t.Run("positive case: rcon", func(t *testing.T) {
+		_, clt := pipe()
+		defer clt.Close()
+
+		c := AsClient(clt)
+		assert.Same(t, clt, c)
+	})
+
+	t.Run("positive case: other implementation", func(t *testing.T) {
+		srv, clt := net.Pipe()
+		defer srv.Close()
+		defer clt.Close()
+
+		m := &mockRCON{Conn: clt, responses: map[string]string{"/seed": "Seed: [1]"}}
+		c := AsClient(m)
+
+		res, err := c.Command("/seed")
+		assert.NoError(t, err)
+		assert.Equal(t, "Seed: [1]", res)
+
+		_, err = c.Command("/unknown")
+		assert.Error(t, err)
+
+		assert.Equal(t, Stats{Commands: 1, Failures: 1}, c.Stats())
+		assert.Equal(t, clt.LocalAddr(), c.LocalAddr())
+		assert.Equal(t, clt.RemoteAddr(), c.RemoteAddr())
+		assert.NoError(t, c.SetDeadline(time.Now().Add(mockTimeout)))
+		assert.NoError(t, c.Close())
+	})
+	t.Run("positive case: implementation without CommandContext", func(t *testing.T) {
+		srv, clt := net.Pipe()
+		defer srv.Close()
+		defer clt.Close()
+
+		// NOTE: only the methods of RCON are promoted
+		m := struct{ RCON }{&mockRCON{Conn: clt, responses: map[string]string{"/seed": "Seed: [1]"}}}
+		c := AsClient(m)
+
+		res, err := c.CommandContext(context.Background(), "/seed")
+		assert.NoError(t, err)
+		assert.Equal(t, "Seed: [1]", res)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err = c.CommandContext(ctx, "/seed")
+		assert.IsType(t, &RCONError{}, err)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		assert.Equal(t, Stats{Commands: 1, Failures: 1}, c.Stats())
+	})
+}
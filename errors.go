@@ -21,7 +21,10 @@
 package rcon
 
 import (
+	"errors"
 	"fmt"
+
+	"github.com/Aton-Kish/gorcon/protocol"
 )
 
 type RCONError struct {
@@ -48,26 +51,24 @@ func (e *RCONError) Unwrap() error {
 	return e.Err
 }
 
-type PacketError struct {
-	Op  string
-	Err error
-}
+type PacketError = protocol.PacketError
 
-func (e *PacketError) Error() string {
-	if e == nil {
-		return "<nil>"
-	}
+// unsentError wraps an error raised before a request was sent, such as a
+// context done while waiting for a limiter token. It says nothing about the
+// server.
+type unsentError struct {
+	err error
+}
 
-	var err string
-	if e.Err == nil {
-		err = "<nil>"
-	} else {
-		err = e.Err.Error()
-	}
+func (e *unsentError) Error() string {
+	return e.err.Error()
+}
 
-	return fmt.Sprintf("packet %s: %s", e.Op, err)
+func (e *unsentError) Unwrap() error {
+	return e.err
 }
 
-func (e *PacketError) Unwrap() error {
-	return e.Err
+func isUnsent(err error) bool {
+	var u *unsentError
+	return errors.As(err, &u)
 }
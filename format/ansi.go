@@ -0,0 +1,97 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+)
+
+// ansiColors are the SGR codes of the named colors, in palette order.
+var ansiColors = []int{30, 34, 32, 36, 31, 35, 33, 37, 90, 94, 92, 96, 91, 95, 93, 97}
+
+// ANSI converts the formatting codes of s into ANSI escape sequences for
+// terminals. Named colors use the 16 standard colors and hex colors use 24-bit
+// colors. Obfuscated text blinks. The output ends with a reset if it is
+// styled.
+func ANSI(s string) string {
+	var (
+		b      strings.Builder
+		styled bool
+	)
+	for _, seg := range Parse(s) {
+		sgr := ansiSGR(seg.Style)
+		if sgr != "" || styled {
+			b.WriteString("\x1b[0" + sgr + "m")
+		}
+		styled = sgr != ""
+
+		b.WriteString(seg.Text)
+	}
+
+	if styled {
+		b.WriteString("\x1b[0m")
+	}
+
+	return b.String()
+}
+
+// ansiSGR returns the SGR parameters of style, each preceded by a semicolon.
+func ansiSGR(style Style) string {
+	var b strings.Builder
+
+	if style.Color != "" {
+		if i := paletteIndex(style.Color); i >= 0 {
+			fmt.Fprintf(&b, ";%d", ansiColors[i])
+		} else if rgb, ok := rgbOf(style.Color); ok {
+			fmt.Fprintf(&b, ";38;2;%d;%d;%d", rgb>>16&0xFF, rgb>>8&0xFF, rgb&0xFF)
+		}
+	}
+
+	for _, f := range []struct {
+		set bool
+		sgr string
+	}{
+		{style.Bold, ";1"},
+		{style.Italic, ";3"},
+		{style.Underlined, ";4"},
+		{style.Obfuscated, ";5"},
+		{style.Strikethrough, ";9"},
+	} {
+		if f.set {
+			b.WriteString(f.sgr)
+		}
+	}
+
+	return b.String()
+}
+
+func paletteIndex(color minecraft.Color) int {
+	for i, p := range palette {
+		if p.color == color {
+			return i
+		}
+	}
+
+	return -1
+}
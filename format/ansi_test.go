@@ -0,0 +1,71 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package format
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestANSI(t *testing.T) {
+	cases := []struct {
+		name     string
+		s        string
+		expected string
+	}{
+		{
+			name:     "positive case: plain",
+			s:        "hello",
+			expected: "hello",
+		},
+		{
+			name:     "positive case: color",
+			s:        "§cError§r ok",
+			expected: "\x1b[0;91mError\x1b[0m ok",
+		},
+		{
+			name:     "positive case: formats",
+			s:        "§4§l§o§n§m§kx",
+			expected: "\x1b[0;31;1;3;4;5;9mx\x1b[0m",
+		},
+		{
+			name:     "positive case: hex",
+			s:        "§x§f§f§5§5§0§0x",
+			expected: "\x1b[0;38;2;255;85;0mx\x1b[0m",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ANSI(tt.s))
+		})
+	}
+}
+
+func TestANSI_allColors(t *testing.T) {
+	for i, p := range palette {
+		assert.Equal(t, "\x1b[0;"+strconv.Itoa(ansiColors[i])+"mx\x1b[0m", ANSI("§"+string(p.code)+"x"))
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package format renders the legacy `§` formatting codes found in server
+// output, such as that of Paper, Spigot and plugins.
+//
+// Color codes `§0` to `§f` and hex colors `§x§R§R§G§G§B§B` reset the
+// formatting, as they do in the game. Format codes `§k` to `§o` add to it,
+// and `§r` resets everything. Unknown codes are kept as text, and an
+// incomplete `§x` is dropped.
+package format
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+)
+
+// Prefix starts a formatting code.
+const Prefix = "§"
+
+// Style is the formatting of a Segment. An empty Color is the default color.
+type Style struct {
+	Color         minecraft.Color
+	Bold          bool
+	Italic        bool
+	Underlined    bool
+	Strikethrough bool
+	Obfuscated    bool
+}
+
+// Segment is a run of text with a single style.
+type Segment struct {
+	Text  string
+	Style Style
+}
+
+// palette lists the color codes in order, with their RGB values.
+var palette = []struct {
+	code  byte
+	color minecraft.Color
+	rgb   uint32
+}{
+	{'0', minecraft.Black, 0x000000},
+	{'1', minecraft.DarkBlue, 0x0000AA},
+	{'2', minecraft.DarkGreen, 0x00AA00},
+	{'3', minecraft.DarkAqua, 0x00AAAA},
+	{'4', minecraft.DarkRed, 0xAA0000},
+	{'5', minecraft.DarkPurple, 0xAA00AA},
+	{'6', minecraft.Gold, 0xFFAA00},
+	{'7', minecraft.Gray, 0xAAAAAA},
+	{'8', minecraft.DarkGray, 0x555555},
+	{'9', minecraft.Blue, 0x5555FF},
+	{'a', minecraft.Green, 0x55FF55},
+	{'b', minecraft.Aqua, 0x55FFFF},
+	{'c', minecraft.Red, 0xFF5555},
+	{'d', minecraft.LightPurple, 0xFF55FF},
+	{'e', minecraft.Yellow, 0xFFFF55},
+	{'f', minecraft.White, 0xFFFFFF},
+}
+
+// Parse splits s into styled segments. Adjacent segments never share a
+// style, and no segment is empty.
+func Parse(s string) []Segment {
+	segments := []Segment{}
+
+	var (
+		style Style
+		text  strings.Builder
+	)
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+
+		if n := len(segments); n > 0 && segments[n-1].Style == style {
+			segments[n-1].Text += text.String()
+		} else {
+			segments = append(segments, Segment{Text: text.String(), Style: style})
+		}
+		text.Reset()
+	}
+
+	for len(s) > 0 {
+		i := strings.Index(s, Prefix)
+		if i < 0 {
+			text.WriteString(s)
+			break
+		}
+
+		text.WriteString(s[:i])
+		s = s[i+len(Prefix):]
+
+		if len(s) == 0 {
+			// NOTE: a trailing prefix is kept as text
+			text.WriteString(Prefix)
+			break
+		}
+
+		code := lower(s[0])
+		switch {
+		case code == 'x':
+			flush()
+			color, n := parseHex(s[1:])
+			if n > 0 {
+				style = Style{Color: color}
+			}
+			s = s[1+n:]
+			continue
+		case colorOf(code) != "":
+			flush()
+			style = Style{Color: colorOf(code)}
+		case code == 'k':
+			flush()
+			style.Obfuscated = true
+		case code == 'l':
+			flush()
+			style.Bold = true
+		case code == 'm':
+			flush()
+			style.Strikethrough = true
+		case code == 'n':
+			flush()
+			style.Underlined = true
+		case code == 'o':
+			flush()
+			style.Italic = true
+		case code == 'r':
+			flush()
+			style = Style{}
+		default:
+			text.WriteString(Prefix)
+			continue
+		}
+
+		s = s[1:]
+	}
+	flush()
+
+	return segments
+}
+
+// parseHex parses the `§R§R§G§G§B§B` following `§x`, and returns the color and
+// the number of bytes read, or zero if the sequence is incomplete.
+func parseHex(s string) (minecraft.Color, int) {
+	var digits [6]byte
+	n := 0
+	for i := range digits {
+		if !strings.HasPrefix(s[n:], Prefix) || len(s) < n+len(Prefix)+1 {
+			return "", 0
+		}
+		n += len(Prefix)
+
+		c := lower(s[n])
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return "", 0
+		}
+		digits[i] = c
+		n++
+	}
+
+	rgb, _ := strconv.ParseUint(string(digits[:]), 16, 32)
+
+	return minecraft.Hex(uint32(rgb)), n
+}
+
+// Strip removes the formatting codes of s.
+func Strip(s string) string {
+	var b strings.Builder
+	for _, seg := range Parse(s) {
+		b.WriteString(seg.Text)
+	}
+
+	return b.String()
+}
+
+// Legacy encodes segments back into formatting codes.
+func Legacy(segments []Segment) string {
+	var (
+		b    strings.Builder
+		prev Style
+	)
+	for _, seg := range segments {
+		if seg.Style != prev {
+			b.WriteString(codes(seg.Style, prev))
+			prev = seg.Style
+		}
+		b.WriteString(seg.Text)
+	}
+
+	return b.String()
+}
+
+// codes returns the codes changing the style from prev to s.
+func codes(s, prev Style) string {
+	var b strings.Builder
+
+	if s.Color != prev.Color || removesFormat(s, prev) {
+		switch {
+		case s.Color == "":
+			b.WriteString(Prefix + "r")
+		case s.Color[0] == '#':
+			b.WriteString(Prefix + "x")
+			for _, c := range strings.ToLower(string(s.Color[1:])) {
+				b.WriteString(Prefix + string(c))
+			}
+		default:
+			b.WriteString(Prefix + string(codeOf(s.Color)))
+		}
+		prev = Style{Color: s.Color}
+	}
+
+	for _, f := range []struct {
+		set, was bool
+		code     string
+	}{
+		{s.Obfuscated, prev.Obfuscated, "k"},
+		{s.Bold, prev.Bold, "l"},
+		{s.Strikethrough, prev.Strikethrough, "m"},
+		{s.Underlined, prev.Underlined, "n"},
+		{s.Italic, prev.Italic, "o"},
+	} {
+		if f.set && !f.was {
+			b.WriteString(Prefix + f.code)
+		}
+	}
+
+	return b.String()
+}
+
+// removesFormat reports whether any format of prev is unset in s, which
+// requires a reset.
+func removesFormat(s, prev Style) bool {
+	return prev.Obfuscated && !s.Obfuscated || prev.Bold && !s.Bold || prev.Strikethrough && !s.Strikethrough ||
+		prev.Underlined && !s.Underlined || prev.Italic && !s.Italic
+}
+
+func colorOf(code byte) minecraft.Color {
+	for _, p := range palette {
+		if p.code == code {
+			return p.color
+		}
+	}
+
+	return ""
+}
+
+func codeOf(color minecraft.Color) byte {
+	for _, p := range palette {
+		if p.color == color {
+			return p.code
+		}
+	}
+
+	return 'r'
+}
+
+// rgbOf returns the RGB value of a named or hex color.
+func rgbOf(color minecraft.Color) (uint32, bool) {
+	for _, p := range palette {
+		if p.color == color {
+			return p.rgb, true
+		}
+	}
+
+	if len(color) == 7 && color[0] == '#' {
+		if rgb, err := strconv.ParseUint(string(color[1:]), 16, 32); err == nil {
+			return uint32(rgb), true
+		}
+	}
+
+	return 0, false
+}
+
+func lower(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+
+	return c
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package format
+
+import (
+	"testing"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+	"github.com/stretchr/testify/assert"
+)
+
+// allCodes are every formatting code, including a hex color.
+var allCodes = []string{
+	"§0", "§1", "§2", "§3", "§4", "§5", "§6", "§7", "§8", "§9", "§a", "§b", "§c", "§d", "§e", "§f",
+	"§k", "§l", "§m", "§n", "§o", "§r", "§x§f§f§5§5§0§0",
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name     string
+		s        string
+		expected []Segment
+	}{
+		{
+			name:     "positive case: plain",
+			s:        "hello",
+			expected: []Segment{{Text: "hello"}},
+		},
+		{
+			name:     "positive case: empty",
+			s:        "",
+			expected: []Segment{},
+		},
+		{
+			name: "positive case: color",
+			s:    "§cError: §fdone",
+			expected: []Segment{
+				{Text: "Error: ", Style: Style{Color: minecraft.Red}},
+				{Text: "done", Style: Style{Color: minecraft.White}},
+			},
+		},
+		{
+			name: "positive case: formats",
+			s:    "§l§obold italic§r plain",
+			expected: []Segment{
+				{Text: "bold italic", Style: Style{Bold: true, Italic: true}},
+				{Text: " plain"},
+			},
+		},
+		{
+			name: "positive case: color resets formats",
+			s:    "§l§nA§aB",
+			expected: []Segment{
+				{Text: "A", Style: Style{Bold: true, Underlined: true}},
+				{Text: "B", Style: Style{Color: minecraft.Green}},
+			},
+		},
+		{
+			name: "positive case: all formats",
+			s:    "§k§l§m§n§ox",
+			expected: []Segment{
+				{Text: "x", Style: Style{Obfuscated: true, Bold: true, Strikethrough: true, Underlined: true, Italic: true}},
+			},
+		},
+		{
+			name: "positive case: uppercase",
+			s:    "§Ax§Ly",
+			expected: []Segment{
+				{Text: "x", Style: Style{Color: minecraft.Green}},
+				{Text: "y", Style: Style{Color: minecraft.Green, Bold: true}},
+			},
+		},
+		{
+			name: "positive case: hex",
+			s:    "§x§F§F§5§5§0§0orange§lbold",
+			expected: []Segment{
+				{Text: "orange", Style: Style{Color: "#FF5500"}},
+				{Text: "bold", Style: Style{Color: "#FF5500", Bold: true}},
+			},
+		},
+		{
+			name: "positive case: incomplete hex",
+			s:    "§x§f§fab",
+			expected: []Segment{
+				{Text: "ab", Style: Style{Color: minecraft.White}},
+			},
+		},
+		{
+			name: "positive case: redundant codes",
+			s:    "§aa§ab",
+			expected: []Segment{
+				{Text: "ab", Style: Style{Color: minecraft.Green}},
+			},
+		},
+		{
+			name:     "positive case: unknown code",
+			s:        "§zx",
+			expected: []Segment{{Text: "§zx"}},
+		},
+		{
+			name:     "positive case: trailing prefix",
+			s:        "x§",
+			expected: []Segment{{Text: "x§"}},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Parse(tt.s))
+		})
+	}
+}
+
+func TestStrip(t *testing.T) {
+	assert.Equal(t, "There are 2 players: jeb_, Notch", Strip("§6There are §c2§6 players: §rjeb_, §x§1§2§3§4§5§6Notch"))
+
+	for _, code := range allCodes {
+		assert.Equal(t, "ab", Strip("a"+code+"b"), code)
+	}
+}
+
+func TestLegacy(t *testing.T) {
+	segments := []Segment{
+		{Text: "a", Style: Style{Color: minecraft.Red, Bold: true}},
+		{Text: "b", Style: Style{Color: minecraft.Red, Bold: true, Italic: true}},
+		{Text: "c", Style: Style{Color: minecraft.Red}},
+		{Text: "d", Style: Style{Color: "#00FF00"}},
+		{Text: "e"},
+	}
+
+	assert.Equal(t, "§c§la§ob§cc§x§0§0§f§f§0§0d§re", Legacy(segments))
+}
+
+func TestLegacy_roundTrip(t *testing.T) {
+	for _, a := range allCodes {
+		for _, b := range allCodes {
+			s := "x" + a + "y" + b + "z"
+			segments := Parse(s)
+
+			assert.Equal(t, segments, Parse(Legacy(segments)), s)
+		}
+	}
+}
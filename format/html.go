@@ -0,0 +1,86 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package format
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// HTML converts the formatting codes of s into HTML. The text is escaped and
+// styled segments are wrapped in spans with inline styles built only from
+// known colors and formats, so the output is safe to embed. Obfuscated text
+// gets the class `obfuscated`.
+func HTML(s string) string {
+	var b strings.Builder
+	for _, seg := range Parse(s) {
+		text := strings.ReplaceAll(html.EscapeString(seg.Text), "\n", "<br>")
+
+		css, class := htmlStyle(seg.Style)
+		if css == "" && class == "" {
+			b.WriteString(text)
+			continue
+		}
+
+		b.WriteString("<span")
+		if class != "" {
+			b.WriteString(` class="` + class + `"`)
+		}
+		if css != "" {
+			b.WriteString(` style="` + css + `"`)
+		}
+		b.WriteString(">" + text + "</span>")
+	}
+
+	return b.String()
+}
+
+func htmlStyle(style Style) (css string, class string) {
+	var decls []string
+
+	if rgb, ok := rgbOf(style.Color); ok {
+		decls = append(decls, fmt.Sprintf("color:#%06X", rgb))
+	}
+	if style.Bold {
+		decls = append(decls, "font-weight:bold")
+	}
+	if style.Italic {
+		decls = append(decls, "font-style:italic")
+	}
+
+	var lines []string
+	if style.Underlined {
+		lines = append(lines, "underline")
+	}
+	if style.Strikethrough {
+		lines = append(lines, "line-through")
+	}
+	if len(lines) > 0 {
+		decls = append(decls, "text-decoration:"+strings.Join(lines, " "))
+	}
+
+	if style.Obfuscated {
+		class = "obfuscated"
+	}
+
+	return strings.Join(decls, ";"), class
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package format
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTML(t *testing.T) {
+	cases := []struct {
+		name     string
+		s        string
+		expected string
+	}{
+		{
+			name:     "positive case: plain",
+			s:        "a & b",
+			expected: "a &amp; b",
+		},
+		{
+			name:     "positive case: color",
+			s:        "§cError",
+			expected: `<span style="color:#FF5555">Error</span>`,
+		},
+		{
+			name:     "positive case: formats",
+			s:        "§6§l§o§n§mx",
+			expected: `<span style="color:#FFAA00;font-weight:bold;font-style:italic;text-decoration:underline line-through">x</span>`,
+		},
+		{
+			name:     "positive case: obfuscated",
+			s:        "§kx",
+			expected: `<span class="obfuscated">x</span>`,
+		},
+		{
+			name:     "positive case: hex",
+			s:        "§x§f§f§5§5§0§0x",
+			expected: `<span style="color:#FF5500">x</span>`,
+		},
+		{
+			name:     "positive case: line break",
+			s:        "a\nb",
+			expected: "a<br>b",
+		},
+		{
+			name:     "positive case: sanitized",
+			s:        `§c<script>alert("x")</script>`,
+			expected: `<span style="color:#FF5555">&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;</span>`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, HTML(tt.s))
+		})
+	}
+}
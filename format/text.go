@@ -0,0 +1,102 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package format
+
+import (
+	"strings"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+)
+
+// Text converts the formatting codes of s into a JSON text component, with a
+// child per segment. Only formats that are set are written, since children
+// inherit from the unstyled root.
+func Text(s string) minecraft.Text {
+	root := minecraft.Text{}
+	for _, seg := range Parse(s) {
+		t := minecraft.NewText(seg.Text).WithColor(seg.Style.Color)
+		if seg.Style.Bold {
+			t = t.WithBold(true)
+		}
+		if seg.Style.Italic {
+			t = t.WithItalic(true)
+		}
+		if seg.Style.Underlined {
+			t = t.WithUnderlined(true)
+		}
+		if seg.Style.Strikethrough {
+			t = t.WithStrikethrough(true)
+		}
+		if seg.Style.Obfuscated {
+			t = t.WithObfuscated(true)
+		}
+
+		root = root.Append(t)
+	}
+
+	return root
+}
+
+// FromText converts a JSON text component into formatting codes, resolving
+// inherited styles. Translated components are written as their keys, and
+// events are dropped.
+func FromText(t minecraft.Text) string {
+	segments := []Segment{}
+	flatten(t, Style{}, &segments)
+
+	return Legacy(segments)
+}
+
+func flatten(t minecraft.Text, parent Style, segments *[]Segment) {
+	style := parent
+	if t.Color != "" {
+		style.Color = t.Color
+		if t.Color[0] == '#' {
+			style.Color = minecraft.Color(strings.ToUpper(string(t.Color)))
+		}
+	}
+	for _, f := range []struct {
+		v   *bool
+		dst *bool
+	}{
+		{t.Bold, &style.Bold},
+		{t.Italic, &style.Italic},
+		{t.Underlined, &style.Underlined},
+		{t.Strikethrough, &style.Strikethrough},
+		{t.Obfuscated, &style.Obfuscated},
+	} {
+		if f.v != nil {
+			*f.dst = *f.v
+		}
+	}
+
+	text := t.Text
+	if t.Translate != "" {
+		text = t.Translate
+	}
+	if text != "" {
+		*segments = append(*segments, Segment{Text: text, Style: style})
+	}
+
+	for _, child := range t.Extra {
+		flatten(child, style, segments)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package format
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestText(t *testing.T) {
+	actual := Text("§cError: §x§0§0§f§f§0§0§ldone")
+
+	assert.Equal(t, `{"text":"","extra":[{"text":"Error: ","color":"red"},{"text":"done","color":"#00FF00","bold":true}]}`, actual.String())
+}
+
+func TestFromText(t *testing.T) {
+	text := minecraft.NewText("a").WithColor(minecraft.Red).WithBold(true).Append(
+		minecraft.NewText("b"),
+		minecraft.NewText("c").WithBold(false).WithColor("#00ff00"),
+		minecraft.NewTranslation("chat.type.text"),
+	)
+
+	assert.Equal(t, "§c§lab§x§0§0§f§f§0§0c§c§lchat.type.text", FromText(text))
+}
+
+func TestText_roundTrip(t *testing.T) {
+	for _, a := range allCodes {
+		for _, b := range allCodes {
+			s := "x" + a + "y" + b + "z"
+
+			raw, err := json.Marshal(Text(s))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var text minecraft.Text
+			if err := json.Unmarshal(raw, &text); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, Parse(s), Parse(FromText(text)), s)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package rcon
+
+import (
+	"time"
+)
+
+// Hooks receives client activity for metrics. Every call carries the server
+// address the connection was dialed with. Implementations must be safe for
+// concurrent use and should return quickly.
+type Hooks interface {
+	// CommandDone is called after every command with its latency, the size
+	// of the response payload and the number of continuation packets.
+	CommandDone(addr string, latency time.Duration, size int, continuations int, err error)
+	AuthFailed(addr string, err error)
+	DialFailed(addr string, err error)
+	Reconnected(addr string)
+}
+
+// NopHooks is a Hooks that does nothing. Embed it to implement a subset of
+// Hooks.
+type NopHooks struct{}
+
+func (NopHooks) CommandDone(addr string, latency time.Duration, size int, continuations int, err error) {
+}
+
+func (NopHooks) AuthFailed(addr string, err error) {}
+
+func (NopHooks) DialFailed(addr string, err error) {}
+
+func (NopHooks) Reconnected(addr string) {}
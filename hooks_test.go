@@ -0,0 +1,118 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package rcon
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aton-Kish/gorcon/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedCommand struct {
+	addr          string
+	size          int
+	continuations int
+	err           error
+}
+
+type recordingHooks struct {
+	NopHooks
+
+	mu       sync.Mutex
+	commands []recordedCommand
+	auths    int
+}
+
+func (h *recordingHooks) CommandDone(addr string, latency time.Duration, size int, continuations int, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.commands = append(h.commands, recordedCommand{addr: addr, size: size, continuations: continuations, err: err})
+}
+
+func (h *recordingHooks) AuthFailed(addr string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.auths++
+}
+
+func TestWithHooks(t *testing.T) {
+	h := new(recordingHooks)
+
+	srv, clt := pipe(WithHooks(h))
+	defer clt.Close()
+	clt.addr = "localhost:25575"
+
+	errCh := make(chan error, 1)
+	defer close(errCh)
+
+	go func() {
+		defer srv.Close()
+
+		req := new(protocol.Packet)
+		if err := srv.dec.Decode(req); err != nil {
+			errCh <- err
+			return
+		}
+
+		// NOTE: reject the password
+		res := protocol.NewPacket(unauthorizedRequestID, protocol.AuthResponseType, []byte{})
+		if err := srv.enc.Encode(res); err != nil {
+			errCh <- err
+			return
+		}
+
+		if err := srv.dec.Decode(req); err != nil {
+			errCh <- err
+			return
+		}
+
+		res = protocol.NewPacket(req.RequestId, protocol.CommandResponseType, []byte("response"))
+		if err := srv.enc.Encode(res); err != nil {
+			errCh <- err
+			return
+		}
+
+		errCh <- nil
+	}()
+
+	assert.Error(t, clt.auth(context.Background(), "tfarcenim"))
+
+	_, err := clt.Command("request")
+	assert.NoError(t, err)
+	assert.NoError(t, <-errCh)
+
+	_, err = clt.Command("request")
+	assert.Error(t, err)
+
+	assert.Equal(t, 1, h.auths)
+	assert.Len(t, h.commands, 2)
+	assert.Equal(t, recordedCommand{addr: "localhost:25575", size: 8}, h.commands[0])
+	assert.Equal(t, "localhost:25575", h.commands[1].addr)
+	assert.Error(t, h.commands[1].err)
+}
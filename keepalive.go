@@ -0,0 +1,525 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package rcon
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	liblog "github.com/Aton-Kish/gorcon/log"
+	"github.com/Aton-Kish/gorcon/protocol"
+)
+
+var ErrClosed = errors.New("client is closed")
+
+const latencySamples = 128
+
+// KeepaliveConfig configures a KeepaliveClient.
+type KeepaliveConfig struct {
+	// Interval between probes. It defaults to 30 seconds. A connection that
+	// was used within Interval, or is in use, is not probed.
+	Interval time.Duration
+	// Timeout of a probe and of a reconnect. It defaults to 10 seconds.
+	Timeout time.Duration
+	// Command is the no-op command used as probe, such as "/list". If empty,
+	// a protocol probe is used instead: a packet of unknown type that the
+	// server answers without running anything.
+	Command string
+}
+
+// LatencyStats summarizes the round-trip times of keepalive probes.
+type LatencyStats struct {
+	Last    time.Duration
+	Average time.Duration
+	P99     time.Duration
+	// Samples is the number of probes the average and the percentile are
+	// computed from.
+	Samples int
+}
+
+// KeepaliveClient is a Client that probes an idle connection in the
+// background to keep NATs and firewalls from dropping it, records the
+// round-trip latency, and reconnects when a probe fails.
+//
+// Deadlines set on the client apply to the current connection only.
+type KeepaliveClient struct {
+	addr     string
+	password string
+	cfg      KeepaliveConfig
+	opts     []Option
+	hooks    Hooks
+	logger   liblog.LeveledLogger
+
+	mu     sync.Mutex
+	conn   *rcon
+	base   Stats
+	closed bool
+	// dialing is closed when the reconnect in progress, if any, returns
+	dialing chan struct{}
+
+	latency latencyRecorder
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+var _ Client = (*KeepaliveClient)(nil)
+
+// DialKeepalive connects to addr and keeps the connection alive until Close.
+func DialKeepalive(ctx context.Context, addr string, password string, cfg KeepaliveConfig, opts ...Option) (*KeepaliveClient, error) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	o := newOptions(opts...)
+
+	conn, err := dial(ctx, new(net.Dialer), addr, password, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &KeepaliveClient{
+		addr:     addr,
+		password: password,
+		cfg:      cfg,
+		opts:     opts,
+		hooks:    o.hooks,
+		logger:   o.logger,
+		conn:     conn,
+		done:     make(chan struct{}),
+	}
+
+	k.wg.Add(1)
+	go k.loop()
+
+	return k, nil
+}
+
+func (k *KeepaliveClient) loop() {
+	defer k.wg.Done()
+
+	ticker := time.NewTicker(k.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-k.done:
+			return
+		case <-ticker.C:
+			k.keepalive()
+		}
+	}
+}
+
+func (k *KeepaliveClient) keepalive() {
+	ctx, cancel := context.WithTimeout(context.Background(), k.cfg.Timeout)
+	defer cancel()
+
+	k.mu.Lock()
+	conn := k.conn
+	k.mu.Unlock()
+
+	if conn == nil {
+		_, _ = k.reconnect(ctx)
+		return
+	}
+
+	// NOTE: traffic keeps the connection alive as well as a probe does
+	if conn.idle() < k.cfg.Interval {
+		return
+	}
+
+	rtt, ok, err := conn.probe(ctx, k.cfg.Command)
+	if !ok {
+		return
+	}
+
+	if err != nil {
+		resolveLogger(ctx, k.logger).Log(liblog.LevelWarn, "keepalive probe failed", liblog.String("func", getFuncName()), liblog.String("addr", k.addr), liblog.Err(err))
+		k.drop(conn)
+		_, _ = k.reconnect(ctx)
+		return
+	}
+
+	k.latency.record(rtt)
+}
+
+// drop closes conn if it is still the current connection.
+func (k *KeepaliveClient) drop(conn *rcon) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.conn != conn {
+		return
+	}
+
+	k.base = addStats(k.base, conn.Stats())
+	k.conn = nil
+	_ = conn.Close()
+}
+
+// reconnect dials a new connection unless one is already established. The
+// dial runs without holding k.mu, and concurrent callers wait for it instead
+// of dialing again.
+func (k *KeepaliveClient) reconnect(ctx context.Context) (*rcon, error) {
+	k.mu.Lock()
+	for {
+		if k.closed {
+			k.mu.Unlock()
+			return nil, &RCONError{Op: "dial", Err: ErrClosed}
+		}
+
+		if k.conn != nil {
+			conn := k.conn
+			k.mu.Unlock()
+			return conn, nil
+		}
+
+		if k.dialing == nil {
+			break
+		}
+
+		dialing := k.dialing
+		k.mu.Unlock()
+
+		select {
+		case <-dialing:
+		case <-ctx.Done():
+			return nil, &RCONError{Op: "dial", Err: ctx.Err()}
+		}
+
+		k.mu.Lock()
+	}
+
+	dialing := make(chan struct{})
+	k.dialing = dialing
+	k.mu.Unlock()
+
+	conn, err := k.dial(ctx)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.dialing = nil
+	close(dialing)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if k.closed {
+		_ = conn.Close()
+		return nil, &RCONError{Op: "dial", Err: ErrClosed}
+	}
+
+	k.conn = conn
+	k.hooks.Reconnected(k.addr)
+
+	return conn, nil
+}
+
+// dial connects to the server, giving up when ctx is done or k is closed.
+func (k *KeepaliveClient) dial(ctx context.Context) (*rcon, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		select {
+		case <-k.done:
+			cancel()
+		case <-stop:
+		}
+	}()
+
+	return dial(ctx, new(net.Dialer), k.addr, k.password, k.opts...)
+}
+
+// current returns the current connection, reconnecting if there is none.
+func (k *KeepaliveClient) current(ctx context.Context) (*rcon, error) {
+	k.mu.Lock()
+	conn, closed := k.conn, k.closed
+	k.mu.Unlock()
+
+	if closed {
+		return nil, &RCONError{Op: "command", Err: ErrClosed}
+	}
+
+	if conn != nil {
+		return conn, nil
+	}
+
+	return k.reconnect(ctx)
+}
+
+func (k *KeepaliveClient) Command(command string) (string, error) {
+	return k.CommandContext(context.Background(), command)
+}
+
+func (k *KeepaliveClient) CommandContext(ctx context.Context, command string) (string, error) {
+	conn, err := k.current(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := conn.CommandContext(ctx, command)
+	if conn.broken.Load() {
+		// NOTE: reconnect on the next command instead of the next probe
+		k.drop(conn)
+	}
+
+	return res, err
+}
+
+// CommandAsync sends the command in the background. The returned channel
+// receives exactly one Result.
+func (k *KeepaliveClient) CommandAsync(command string) <-chan Result {
+	ch := make(chan Result, 1)
+
+	go func() {
+		res, err := k.CommandContext(context.Background(), command)
+		ch <- Result{Command: command, Response: res, Err: err}
+	}()
+
+	return ch
+}
+
+func (k *KeepaliveClient) commandBatch(ctx context.Context, commands []string) []Result {
+	conn, err := k.current(ctx)
+	if err != nil {
+		results := make([]Result, len(commands))
+		for i, command := range commands {
+			results[i] = Result{Command: command, Err: err}
+		}
+
+		return results
+	}
+
+	results := conn.commandBatch(ctx, commands)
+	if conn.broken.Load() {
+		k.drop(conn)
+	}
+
+	return results
+}
+
+// Latency returns the round-trip times of the recent probes.
+func (k *KeepaliveClient) Latency() LatencyStats {
+	return k.latency.stats()
+}
+
+// Close stops the keepalive and closes the connection.
+func (k *KeepaliveClient) Close() error {
+	k.mu.Lock()
+	if k.closed {
+		k.mu.Unlock()
+		return nil
+	}
+
+	k.closed = true
+	close(k.done)
+	conn := k.conn
+	k.mu.Unlock()
+
+	k.wg.Wait()
+
+	if conn == nil {
+		return nil
+	}
+
+	return conn.Close()
+}
+
+func (k *KeepaliveClient) LocalAddr() net.Addr {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.conn == nil {
+		return nil
+	}
+
+	return k.conn.LocalAddr()
+}
+
+func (k *KeepaliveClient) RemoteAddr() net.Addr {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.conn == nil {
+		return nil
+	}
+
+	return k.conn.RemoteAddr()
+}
+
+func (k *KeepaliveClient) SetDeadline(t time.Time) error {
+	return k.setDeadline(func(c net.Conn) error { return c.SetDeadline(t) })
+}
+
+func (k *KeepaliveClient) SetReadDeadline(t time.Time) error {
+	return k.setDeadline(func(c net.Conn) error { return c.SetReadDeadline(t) })
+}
+
+func (k *KeepaliveClient) SetWriteDeadline(t time.Time) error {
+	return k.setDeadline(func(c net.Conn) error { return c.SetWriteDeadline(t) })
+}
+
+func (k *KeepaliveClient) setDeadline(set func(c net.Conn) error) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.conn == nil {
+		return nil
+	}
+
+	return set(k.conn)
+}
+
+// Stats returns the counters of every connection made by the client.
+func (k *KeepaliveClient) Stats() Stats {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.conn == nil {
+		return k.base
+	}
+
+	return addStats(k.base, k.conn.Stats())
+}
+
+func addStats(a Stats, b Stats) Stats {
+	return Stats{
+		Commands:        a.Commands + b.Commands,
+		Failures:        a.Failures + b.Failures,
+		PacketsSent:     a.PacketsSent + b.PacketsSent,
+		PacketsReceived: a.PacketsReceived + b.PacketsReceived,
+	}
+}
+
+// probe measures the round-trip time of command, or of a protocol probe if
+// command is empty. It bypasses the middleware chain, and probes are neither
+// counted as commands in Stats nor reported to Hooks. If a request is in
+// flight, probe returns false without waiting for it.
+func (c *rcon) probe(ctx context.Context, command string) (time.Duration, bool, error) {
+	if !c.mu.TryLock() {
+		return 0, false, nil
+	}
+	defer c.mu.Unlock()
+
+	// NOTE: the probe itself does not count as activity
+	last := c.lastActive.Load()
+	defer c.lastActive.Store(last)
+
+	if err := ctx.Err(); err != nil {
+		return 0, true, &RCONError{Op: "probe", Err: err}
+	}
+
+	start := time.Now()
+
+	if command != "" {
+		if err := c.validate(command); err != nil {
+			return 0, true, &RCONError{Op: "probe", Err: err}
+		}
+
+		if _, _, err := c.request(ctx, rand.Int31(), protocol.CommandRequestType, []byte(command)); err != nil {
+			return 0, true, &RCONError{Op: "probe", Err: err}
+		}
+
+		return time.Since(start), true, nil
+	}
+
+	stop := c.watch(ctx)
+
+	// NOTE: the server answers a packet of unknown type with "Unknown request"
+	req := protocol.NewPacket(rand.Int31(), protocol.DummyRequestType, []byte{})
+	err := c.encode(ctx, req)
+	if err == nil {
+		err = c.decode(ctx, new(protocol.Packet), false)
+	}
+	stop(err)
+
+	if err != nil {
+		return 0, true, &RCONError{Op: "probe", Err: err}
+	}
+
+	return time.Since(start), true, nil
+}
+
+type latencyRecorder struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+	last    time.Duration
+}
+
+func (r *latencyRecorder) record(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.last = d
+	if len(r.samples) < latencySamples {
+		r.samples = append(r.samples, d)
+		return
+	}
+
+	r.samples[r.next] = d
+	r.next = (r.next + 1) % latencySamples
+}
+
+func (r *latencyRecorder) stats() LatencyStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := len(r.samples)
+	if n == 0 {
+		return LatencyStats{}
+	}
+
+	sorted := make([]time.Duration, n)
+	copy(sorted, r.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+
+	// NOTE: nearest-rank percentile
+	rank := (99*n + 99) / 100
+
+	return LatencyStats{
+		Last:    r.last,
+		Average: sum / time.Duration(n),
+		P99:     sorted[rank-1],
+		Samples: n,
+	}
+}
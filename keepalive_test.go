@@ -0,0 +1,371 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package rcon
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Aton-Kish/gorcon/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockServer accepts connections, authenticates them with mockPassword,
+// echoes commands, splits the response to "/help", answers "/slow" after
+// slowDelay, closes the connection on "/stop" and answers unknown packet
+// types like Minecraft. Each
+// connection is dropped after maxProbes probes if maxProbes is positive.
+const slowDelay = 400 * time.Millisecond
+
+type mockServer struct {
+	l         net.Listener
+	maxProbes int
+	accepted  atomic.Int32
+	wg        sync.WaitGroup
+}
+
+func newMockServer(t *testing.T, maxProbes int) *mockServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &mockServer{l: l, maxProbes: maxProbes}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			s.accepted.Add(1)
+			s.wg.Add(1)
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *mockServer) serve(conn net.Conn) {
+	defer s.wg.Done()
+	defer conn.Close()
+
+	enc, dec := protocol.NewEncoder(conn), protocol.NewDecoder(conn)
+
+	probes := 0
+	for {
+		req := new(protocol.Packet)
+		if err := dec.Decode(req); err != nil {
+			return
+		}
+
+		var res *protocol.Packet
+		switch req.PacketType {
+		case protocol.AuthRequestType:
+			if string(req.Payload) == mockPassword {
+				res = protocol.NewPacket(req.RequestId, protocol.AuthResponseType, []byte{})
+			} else {
+				res = protocol.NewPacket(unauthorizedRequestID, protocol.AuthResponseType, []byte{})
+			}
+		case protocol.CommandRequestType:
+			if string(req.Payload) == "/stop" {
+				return
+			}
+
+			if string(req.Payload) == "/slow" {
+				time.Sleep(slowDelay)
+			}
+
+			if string(req.Payload) == "/help" {
+				// NOTE: split response
+				big := protocol.NewPacket(req.RequestId, protocol.CommandResponseType, []byte(strings.Repeat("a", maxResponsePayloadSize)))
+				if err := enc.Encode(big); err != nil {
+					return
+				}
+
+				res = protocol.NewPacket(req.RequestId, protocol.CommandResponseType, []byte("b"))
+				break
+			}
+
+			res = protocol.NewPacket(req.RequestId, protocol.CommandResponseType, req.Payload)
+		default:
+			probes++
+			if s.maxProbes > 0 && probes > s.maxProbes {
+				return
+			}
+
+			res = protocol.NewPacket(req.RequestId, protocol.CommandResponseType, []byte("Unknown request 64"))
+		}
+
+		if err := enc.Encode(res); err != nil {
+			return
+		}
+	}
+}
+
+func (s *mockServer) addr() string {
+	return s.l.Addr().String()
+}
+
+func (s *mockServer) close() {
+	s.l.Close()
+	s.wg.Wait()
+}
+
+type reconnectHooks struct {
+	NopHooks
+
+	reconnects atomic.Int32
+}
+
+func (h *reconnectHooks) Reconnected(addr string) {
+	h.reconnects.Add(1)
+}
+
+func TestDialKeepalive(t *testing.T) {
+	t.Run("positive case: latency", func(t *testing.T) {
+		s := newMockServer(t, 0)
+		defer s.close()
+
+		k, err := DialKeepalive(context.Background(), s.addr(), mockPassword, KeepaliveConfig{Interval: 5 * time.Millisecond})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Eventually(t, func() bool { return k.Latency().Samples >= 3 }, time.Second, time.Millisecond)
+		assert.NoError(t, k.Close())
+
+		latency := k.Latency()
+		assert.Greater(t, latency.Last, time.Duration(0))
+		assert.Greater(t, latency.Average, time.Duration(0))
+		assert.GreaterOrEqual(t, latency.P99, latency.Average)
+
+		_, err = k.Command("request")
+		assert.ErrorIs(t, err, ErrClosed)
+	})
+
+	t.Run("positive case: command probe", func(t *testing.T) {
+		s := newMockServer(t, 0)
+		defer s.close()
+
+		h := new(recordingHooks)
+		k, err := DialKeepalive(context.Background(), s.addr(), mockPassword, KeepaliveConfig{Interval: 5 * time.Millisecond, Command: "/list"}, WithHooks(h))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer k.Close()
+
+		assert.Eventually(t, func() bool { return k.Latency().Samples >= 2 }, time.Second, time.Millisecond)
+
+		// NOTE: probes are not user commands
+		stats := k.Stats()
+		assert.Equal(t, uint64(0), stats.Commands)
+		assert.Equal(t, uint64(0), stats.Failures)
+		assert.GreaterOrEqual(t, stats.PacketsSent, uint64(2))
+
+		h.mu.Lock()
+		assert.Empty(t, h.commands)
+		h.mu.Unlock()
+	})
+
+	t.Run("positive case: slow command", func(t *testing.T) {
+		s := newMockServer(t, 0)
+		defer s.close()
+
+		k, err := DialKeepalive(context.Background(), s.addr(), mockPassword, KeepaliveConfig{Interval: 50 * time.Millisecond, Timeout: 100 * time.Millisecond})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer k.Close()
+
+		// NOTE: probes must not queue behind the command and time out
+		res, err := k.Command("/slow")
+		assert.NoError(t, err)
+		assert.Equal(t, "/slow", res)
+
+		assert.Eventually(t, func() bool { return k.Latency().Samples >= 1 }, time.Second, time.Millisecond)
+		assert.Equal(t, int32(1), s.accepted.Load())
+		assert.Less(t, k.Latency().P99, slowDelay)
+	})
+
+	t.Run("positive case: close while reconnecting", func(t *testing.T) {
+		s := newMockServer(t, 0)
+
+		k, err := DialKeepalive(context.Background(), s.addr(), mockPassword, KeepaliveConfig{Interval: time.Hour})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// NOTE: a listener that accepts but never answers keeps the dial in
+		// the auth exchange
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+		go func() {
+			for {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+			}
+		}()
+		k.addr = l.Addr().String()
+		k.drop(k.conn)
+		s.close()
+
+		dialed := make(chan error, 1)
+		go func() {
+			_, err := k.Command("request")
+			dialed <- err
+		}()
+
+		assert.Eventually(t, func() bool {
+			k.mu.Lock()
+			defer k.mu.Unlock()
+			return k.dialing != nil
+		}, time.Second, time.Millisecond)
+
+		// NOTE: the lock is not held while dialing
+		assert.Nil(t, k.LocalAddr())
+
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			assert.NoError(t, k.Close())
+		}()
+
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			t.Fatal("Close blocked by the dial")
+		}
+
+		assert.Error(t, <-dialed)
+	})
+
+	t.Run("positive case: reconnect", func(t *testing.T) {
+		s := newMockServer(t, 1)
+		defer s.close()
+
+		h := new(reconnectHooks)
+		k, err := DialKeepalive(context.Background(), s.addr(), mockPassword, KeepaliveConfig{Interval: 5 * time.Millisecond}, WithHooks(h))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer k.Close()
+
+		assert.Eventually(t, func() bool { return h.reconnects.Load() >= 2 }, time.Second, time.Millisecond)
+		assert.GreaterOrEqual(t, s.accepted.Load(), int32(3))
+
+		res, err := k.Command("request")
+		assert.NoError(t, err)
+		assert.Equal(t, "request", res)
+	})
+
+	t.Run("positive case: reconnect after broken connection", func(t *testing.T) {
+		s := newMockServer(t, 0)
+		defer s.close()
+
+		k, err := DialKeepalive(context.Background(), s.addr(), mockPassword, KeepaliveConfig{Interval: time.Hour})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer k.Close()
+
+		k.conn.breakConn()
+
+		_, err = k.Command("request")
+		assert.ErrorIs(t, err, ErrBroken)
+
+		res, err := k.Command("request")
+		assert.NoError(t, err)
+		assert.Equal(t, "request", res)
+		assert.Equal(t, int32(2), s.accepted.Load())
+	})
+
+	t.Run("positive case: reconnect after dead connection", func(t *testing.T) {
+		s := newMockServer(t, 0)
+		defer s.close()
+
+		k, err := DialKeepalive(context.Background(), s.addr(), mockPassword, KeepaliveConfig{Interval: time.Hour})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer k.Close()
+
+		_, err = k.Command("/stop")
+		assert.Error(t, err)
+
+		res, err := k.Command("request")
+		assert.NoError(t, err)
+		assert.Equal(t, "request", res)
+
+		results := NewBatch("/stop").Run(context.Background(), k)
+		assert.Error(t, results[0].Err)
+
+		results = NewBatch("request").Run(context.Background(), k)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, "request", results[0].Response)
+		assert.Equal(t, int32(3), s.accepted.Load())
+	})
+
+	t.Run("negative case: invalid password", func(t *testing.T) {
+		s := newMockServer(t, 0)
+		defer s.close()
+
+		k, err := DialKeepalive(context.Background(), s.addr(), "tfarcenim", KeepaliveConfig{})
+		assert.IsType(t, &RCONError{}, err)
+		assert.Nil(t, k)
+	})
+}
+
+func Test_latencyRecorder(t *testing.T) {
+	r := new(latencyRecorder)
+	assert.Equal(t, LatencyStats{}, r.stats())
+
+	for i := 1; i <= 200; i++ {
+		r.record(time.Duration(i) * time.Millisecond)
+	}
+
+	// NOTE: only the last 128 samples, 73ms to 200ms, are kept
+	expected := LatencyStats{
+		Last:    200 * time.Millisecond,
+		Average: 136500 * time.Microsecond,
+		P99:     199 * time.Millisecond,
+		Samples: 128,
+	}
+	assert.Equal(t, expected, r.stats())
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package rcon
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Priority orders commands waiting on a Limiter. Higher priorities are served
+// first, and commands of the same priority are served in arrival order.
+type Priority int
+
+const (
+	PriorityBackground  = Priority(-1)
+	PriorityNormal      = Priority(0)
+	PriorityInteractive = Priority(1)
+)
+
+type priorityContextKey struct{}
+
+// ContextWithPriority returns a copy of ctx that carries the priority p.
+// Commands without a priority are sent with PriorityNormal.
+func ContextWithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, p)
+}
+
+func priorityFromContext(ctx context.Context) Priority {
+	p, ok := ctx.Value(priorityContextKey{}).(Priority)
+	if !ok {
+		return PriorityNormal
+	}
+
+	return p
+}
+
+// Limiter is a token bucket rate limiter with a priority queue. It refills
+// perSecond tokens per second up to burst, and every command takes one token.
+// A Limiter may be shared by several connections.
+type Limiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+	waiters waiters
+	seq     uint64
+	timer   *time.Timer
+}
+
+// NewLimiter returns a Limiter that allows perSecond commands per second with
+// bursts of up to burst commands. A non-positive perSecond disables limiting.
+func NewLimiter(perSecond float64, burst int) *Limiter {
+	if perSecond <= 0 {
+		perSecond = math.Inf(1)
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Limiter{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Len returns the number of commands waiting for a token.
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.waiters)
+}
+
+// Wait blocks until a token is available for the priority carried by ctx, or
+// until ctx is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if math.IsInf(l.rate, 1) {
+		return nil
+	}
+
+	l.mu.Lock()
+
+	l.refill()
+	if len(l.waiters) == 0 && l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		l.mu.Unlock()
+		return err
+	}
+
+	w := &waiter{priority: priorityFromContext(ctx), seq: l.seq, ready: make(chan struct{})}
+	l.seq++
+	heap.Push(&l.waiters, w)
+	l.schedule()
+
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if w.index >= 0 {
+			heap.Remove(&l.waiters, w.index)
+		} else {
+			// NOTE: the token was granted concurrently, so give it back
+			l.tokens++
+		}
+		l.schedule()
+
+		return ctx.Err()
+	}
+}
+
+// Middleware returns a Middleware that waits for a token before every command.
+func (l *Limiter) Middleware() Middleware {
+	return func(next CommandFunc) CommandFunc {
+		return func(ctx context.Context, command string) (string, error) {
+			if err := l.Wait(ctx); err != nil {
+				return "", &RCONError{Op: "command", Err: &unsentError{err: err}}
+			}
+
+			return next(ctx, command)
+		}
+	}
+}
+
+// refill adds the tokens accrued since the last refill. l.mu must be held.
+func (l *Limiter) refill() {
+	now := time.Now()
+	if math.IsInf(l.rate, 1) {
+		// NOTE: 0*Inf is NaN when the clock has not moved
+		l.tokens, l.last = l.burst, now
+		return
+	}
+
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+}
+
+// schedule grants tokens to waiters in priority order, and arms a timer for
+// the next token if some are left waiting. l.mu must be held.
+func (l *Limiter) schedule() {
+	l.refill()
+	for len(l.waiters) > 0 && l.tokens >= 1 {
+		w := heap.Pop(&l.waiters).(*waiter)
+		l.tokens--
+		close(w.ready)
+	}
+
+	if len(l.waiters) == 0 || l.timer != nil {
+		return
+	}
+
+	d := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	l.timer = time.AfterFunc(d, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.timer = nil
+		l.schedule()
+	})
+}
+
+type waiter struct {
+	priority Priority
+	seq      uint64
+	ready    chan struct{}
+	index    int
+}
+
+type waiters []*waiter
+
+func (w waiters) Len() int {
+	return len(w)
+}
+
+func (w waiters) Less(i, j int) bool {
+	if w[i].priority != w[j].priority {
+		return w[i].priority > w[j].priority
+	}
+
+	return w[i].seq < w[j].seq
+}
+
+func (w waiters) Swap(i, j int) {
+	w[i], w[j] = w[j], w[i]
+	w[i].index = i
+	w[j].index = j
+}
+
+func (w *waiters) Push(x any) {
+	item := x.(*waiter)
+	item.index = len(*w)
+	*w = append(*w, item)
+}
+
+func (w *waiters) Pop() any {
+	old := *w
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*w = old[:n-1]
+
+	return item
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package rcon
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Wait(t *testing.T) {
+	t.Run("positive case: burst", func(t *testing.T) {
+		l := NewLimiter(1, 3)
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, l.Wait(context.Background()))
+		}
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("positive case: rate", func(t *testing.T) {
+		l := NewLimiter(20, 1)
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, l.Wait(context.Background()))
+		}
+		assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	})
+
+	t.Run("positive case: unlimited", func(t *testing.T) {
+		l := NewLimiter(0, 1)
+
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, l.Wait(context.Background()))
+		}
+
+		// NOTE: the elapsed time times an infinite rate must never be computed
+		l.last = time.Now().Add(time.Hour)
+		l.refill()
+		assert.Equal(t, float64(1), l.tokens)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.NoError(t, l.Wait(ctx))
+	})
+
+	t.Run("negative case: canceled", func(t *testing.T) {
+		l := NewLimiter(1, 1)
+		assert.NoError(t, l.Wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+		assert.Equal(t, 0, l.Len())
+	})
+}
+
+func TestLimiter_priority(t *testing.T) {
+	l := NewLimiter(10, 1)
+	assert.NoError(t, l.Wait(context.Background()))
+
+	var mu sync.Mutex
+	order := []Priority{}
+
+	var wg sync.WaitGroup
+	enqueue := func(p Priority) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := l.Wait(ContextWithPriority(context.Background(), p)); err != nil {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			order = append(order, p)
+		}()
+	}
+
+	// NOTE: enqueue one by one so that arrival order is deterministic
+	priorities := []Priority{PriorityBackground, PriorityBackground, PriorityNormal, PriorityInteractive}
+	for i, p := range priorities {
+		enqueue(p)
+		assert.Eventually(t, func() bool { return l.Len() == i+1 }, time.Second, time.Millisecond)
+	}
+
+	wg.Wait()
+
+	expected := []Priority{PriorityInteractive, PriorityNormal, PriorityBackground, PriorityBackground}
+	assert.Equal(t, expected, order)
+	assert.Equal(t, 0, l.Len())
+}
+
+func TestWithLimiter(t *testing.T) {
+	l := NewLimiter(1, 1)
+	assert.NoError(t, l.Wait(context.Background()))
+
+	srv, clt := pipe(WithLimiter(l))
+	defer srv.Close()
+	defer clt.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := clt.CommandContext(ctx, "request")
+	assert.IsType(t, &RCONError{}, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, Stats{}, clt.Stats())
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package log
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	l := NewStdLogger(log.New(io.Discard, "", 0), LevelInfo)
+
+	cases := []struct {
+		name       string
+		ctx        context.Context
+		expected   LeveledLogger
+		expectedOk bool
+	}{
+		{
+			name:       "positive case",
+			ctx:        NewContext(context.Background(), l),
+			expected:   l,
+			expectedOk: true,
+		},
+		{
+			name:       "negative case: no logger",
+			ctx:        context.Background(),
+			expected:   nil,
+			expectedOk: false,
+		},
+		{
+			name:       "negative case: nil logger",
+			ctx:        NewContext(context.Background(), nil),
+			expected:   nil,
+			expectedOk: false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := FromContext(tt.ctx)
+			assert.Equal(t, tt.expectedOk, ok)
+			if tt.expectedOk {
+				assert.Same(t, tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package log
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Level is the importance of a log record. The values match those of log/slog.
+type Level int
+
+const (
+	LevelDebug = Level(-4)
+	LevelInfo  = Level(0)
+	LevelWarn  = Level(4)
+	LevelError = Level(8)
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// Attr is a key/value pair attached to a log record.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+func Any(key string, value any) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+func String(key string, value string) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+func Int(key string, value int) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+func Err(err error) Attr {
+	return Attr{Key: "error", Value: err}
+}
+
+// LeveledLogger is a structured logger with levels and attributes.
+type LeveledLogger interface {
+	// Enabled reports whether records at level are written, so callers can
+	// skip building expensive attributes.
+	Enabled(level Level) bool
+	Log(level Level, msg string, attrs ...Attr)
+}
+
+// Discard is a LeveledLogger that writes nothing.
+var Discard LeveledLogger = discard{}
+
+type discard struct{}
+
+func (discard) Enabled(level Level) bool {
+	return false
+}
+
+func (discard) Log(level Level, msg string, attrs ...Attr) {}
+
+// NewStdLogger adapts l, typically a *log.Logger, to LeveledLogger. Records
+// below min are dropped, and the rest are written as one logfmt line.
+func NewStdLogger(l Logger, min Level) LeveledLogger {
+	return &stdLogger{l: l, min: min}
+}
+
+type stdLogger struct {
+	l   Logger
+	min Level
+}
+
+func (s *stdLogger) Enabled(level Level) bool {
+	return level >= s.min
+}
+
+func (s *stdLogger) Log(level Level, msg string, attrs ...Attr) {
+	if !s.Enabled(level) {
+		return
+	}
+
+	b := new(strings.Builder)
+	b.WriteString("level=")
+	b.WriteString(level.String())
+	b.WriteString(" msg=")
+	b.WriteString(quote(msg))
+
+	for _, a := range attrs {
+		b.WriteByte(' ')
+		b.WriteString(a.Key)
+		b.WriteByte('=')
+		b.WriteString(quote(fmt.Sprint(a.Value)))
+	}
+
+	s.l.Print(b.String())
+}
+
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\t\r\n") || !strconv.CanBackquote(s) {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
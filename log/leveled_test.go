@@ -0,0 +1,104 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package log
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevel_String(t *testing.T) {
+	cases := []struct {
+		name     string
+		level    Level
+		expected string
+	}{
+		{name: "positive case: debug", level: LevelDebug, expected: "DEBUG"},
+		{name: "positive case: info", level: LevelInfo, expected: "INFO"},
+		{name: "positive case: warn", level: LevelWarn, expected: "WARN"},
+		{name: "positive case: error", level: LevelError, expected: "ERROR"},
+		{name: "positive case: custom", level: Level(2), expected: "LEVEL(2)"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.level.String())
+		})
+	}
+}
+
+func TestNewStdLogger(t *testing.T) {
+	cases := []struct {
+		name     string
+		min      Level
+		level    Level
+		msg      string
+		attrs    []Attr
+		expected string
+	}{
+		{
+			name:     "positive case",
+			min:      LevelInfo,
+			level:    LevelError,
+			msg:      "failed to dial",
+			attrs:    []Attr{String("func", "rcon.dial"), Err(errors.New("rcon dial: refused"))},
+			expected: "level=ERROR msg=\"failed to dial\" func=rcon.dial error=\"rcon dial: refused\"\n",
+		},
+		{
+			name:     "positive case: int and empty value",
+			min:      LevelDebug,
+			level:    LevelDebug,
+			msg:      "packet",
+			attrs:    []Attr{Int("id", 1), Any("payload", "")},
+			expected: "level=DEBUG msg=packet id=1 payload=\"\"\n",
+		},
+		{
+			name:     "positive case: below min",
+			min:      LevelInfo,
+			level:    LevelDebug,
+			msg:      "packet",
+			attrs:    nil,
+			expected: "",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			l := NewStdLogger(log.New(buf, "", 0), tt.min)
+
+			l.Log(tt.level, tt.msg, tt.attrs...)
+			assert.Equal(t, tt.expected, buf.String())
+			assert.Equal(t, tt.level >= tt.min, l.Enabled(tt.level))
+		})
+	}
+}
+
+func TestDiscard(t *testing.T) {
+	assert.False(t, Discard.Enabled(LevelError))
+	Discard.Log(LevelError, "discarded")
+}
@@ -21,7 +21,7 @@
 package rcon
 
 import (
-	"io"
+	"context"
 	"log"
 	"runtime"
 	"sync"
@@ -30,21 +30,54 @@ import (
 )
 
 var (
-	logger liblog.Logger = log.New(io.Discard, "", log.LstdFlags)
-	logmu  sync.Mutex
+	logger liblog.LeveledLogger = liblog.Discard
+	logmu  sync.RWMutex
 )
 
+// SetLogger sets the package-wide logger used by connections without their
+// own logger. Records at LevelInfo and above are written. It is safe for
+// concurrent use.
 func SetLogger(l liblog.Logger) {
+	if l == nil {
+		l = log.Default()
+	}
+
+	SetLeveledLogger(liblog.NewStdLogger(l, liblog.LevelInfo))
+}
+
+// SetLeveledLogger is like SetLogger, but takes a structured logger.
+func SetLeveledLogger(l liblog.LeveledLogger) {
 	logmu.Lock()
 	defer logmu.Unlock()
 
 	if l == nil {
-		l = log.Default()
+		l = liblog.NewStdLogger(log.Default(), liblog.LevelInfo)
 	}
 
 	logger = l
 }
 
+func defaultLogger() liblog.LeveledLogger {
+	logmu.RLock()
+	defer logmu.RUnlock()
+
+	return logger
+}
+
+// resolveLogger picks the logger carried by ctx, then the connection logger l,
+// then the package-wide logger.
+func resolveLogger(ctx context.Context, l liblog.LeveledLogger) liblog.LeveledLogger {
+	if l, ok := liblog.FromContext(ctx); ok {
+		return l
+	}
+
+	if l != nil {
+		return l
+	}
+
+	return defaultLogger()
+}
+
 func getFuncName() string {
 	pc, _, _, ok := runtime.Caller(1)
 	if !ok {
@@ -0,0 +1,132 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package rcon
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"testing"
+
+	liblog "github.com/Aton-Kish/gorcon/log"
+	"github.com/Aton-Kish/gorcon/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLogger(t *testing.T) {
+	defer SetLeveledLogger(liblog.Discard)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			SetLogger(log.New(io.Discard, "", log.LstdFlags))
+		}()
+
+		go func() {
+			defer wg.Done()
+			defaultLogger().Log(liblog.LevelInfo, "concurrent read")
+		}()
+	}
+	wg.Wait()
+}
+
+func Test_resolveLogger(t *testing.T) {
+	global := liblog.NewStdLogger(log.New(io.Discard, "global", 0), liblog.LevelInfo)
+	conn := liblog.NewStdLogger(log.New(io.Discard, "conn", 0), liblog.LevelInfo)
+	scoped := liblog.NewStdLogger(log.New(io.Discard, "scoped", 0), liblog.LevelInfo)
+
+	SetLeveledLogger(global)
+	defer SetLeveledLogger(liblog.Discard)
+
+	cases := []struct {
+		name     string
+		ctx      context.Context
+		logger   liblog.LeveledLogger
+		expected liblog.LeveledLogger
+	}{
+		{
+			name:     "positive case: global",
+			ctx:      context.Background(),
+			logger:   nil,
+			expected: global,
+		},
+		{
+			name:     "positive case: connection",
+			ctx:      context.Background(),
+			logger:   conn,
+			expected: conn,
+		},
+		{
+			name:     "positive case: context",
+			ctx:      liblog.NewContext(context.Background(), scoped),
+			logger:   conn,
+			expected: scoped,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := resolveLogger(tt.ctx, tt.logger)
+			assert.Same(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	srv, conn := net.Pipe()
+	srv.Close()
+
+	clt := newRCON(conn, WithLogger(log.New(buf, "", 0)))
+	defer clt.Close()
+
+	_, err := clt.Command("request")
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), "failed to command")
+}
+
+func TestWithLeveledLogger_packetTrace(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	srv, conn := net.Pipe()
+	defer srv.Close()
+
+	clt := newRCON(conn, WithLeveledLogger(liblog.NewStdLogger(log.New(buf, "", 0), liblog.LevelDebug)))
+	defer clt.Close()
+
+	go func() {
+		p := new(protocol.Packet)
+		_ = protocol.NewDecoder(srv).Decode(p)
+	}()
+
+	err := clt.SendPacket(protocol.NewPacket(123456, protocol.CommandRequestType, []byte("request")))
+	assert.NoError(t, err)
+	assert.Equal(t, "level=DEBUG msg=packet direction=send id=123456 type=2 length=17\n", buf.String())
+}
@@ -0,0 +1,327 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package metrics exports client activity in the Prometheus text format
+// without depending on the Prometheus client library.
+//
+// A Collector implements the Hooks interface of the rcon package:
+//
+//	c := metrics.NewCollector()
+//	http.Handle("/metrics", c)
+//	conn, err := rcon.Dial(addr, password, rcon.WithHooks(c))
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	DefaultSizeBuckets    = []float64{64, 256, 1024, 4096, 16384, 65536, 262144, 1048576}
+)
+
+// Option configures a Collector.
+type Option func(*Collector)
+
+// WithLatencyBuckets sets the upper bounds, in seconds, of the command
+// latency histogram.
+func WithLatencyBuckets(buckets ...float64) Option {
+	return func(c *Collector) {
+		c.latencyBuckets = sortedBuckets(buckets)
+	}
+}
+
+// WithSizeBuckets sets the upper bounds, in bytes, of the response size
+// histogram.
+func WithSizeBuckets(buckets ...float64) Option {
+	return func(c *Collector) {
+		c.sizeBuckets = sortedBuckets(buckets)
+	}
+}
+
+// Collector records client activity per server address.
+type Collector struct {
+	mu             sync.Mutex
+	latencyBuckets []float64
+	sizeBuckets    []float64
+	servers        map[string]*server
+}
+
+type server struct {
+	commands      uint64
+	failures      uint64
+	latency       *histogram
+	size          *histogram
+	continuations uint64
+	authFailures  uint64
+	dialErrors    uint64
+	reconnects    uint64
+}
+
+func NewCollector(opts ...Option) *Collector {
+	c := &Collector{
+		latencyBuckets: DefaultLatencyBuckets,
+		sizeBuckets:    DefaultSizeBuckets,
+		servers:        make(map[string]*server),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// server returns the metrics of addr. c.mu must be held.
+func (c *Collector) server(addr string) *server {
+	s, ok := c.servers[addr]
+	if !ok {
+		s = &server{
+			latency: newHistogram(c.latencyBuckets),
+			size:    newHistogram(c.sizeBuckets),
+		}
+		c.servers[addr] = s
+	}
+
+	return s
+}
+
+func (c *Collector) CommandDone(addr string, latency time.Duration, size int, continuations int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.server(addr)
+	if err != nil {
+		s.failures++
+	} else {
+		s.commands++
+		s.size.observe(float64(size))
+	}
+	s.latency.observe(latency.Seconds())
+	s.continuations += uint64(continuations)
+}
+
+func (c *Collector) AuthFailed(addr string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.server(addr).authFailures++
+}
+
+func (c *Collector) DialFailed(addr string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.server(addr).dialErrors++
+}
+
+func (c *Collector) Reconnected(addr string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.server(addr).reconnects++
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = c.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	// NOTE: hooks run while commands hold their connection, so never write to
+	// a slow reader under the lock
+	addrs, servers := c.snapshot()
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	header(cw, "gorcon_commands_total", "counter", "Number of commands sent.")
+	for _, addr := range addrs {
+		s := servers[addr]
+		sample(cw, "gorcon_commands_total", labels(addr, "result", "success"), float64(s.commands))
+		sample(cw, "gorcon_commands_total", labels(addr, "result", "error"), float64(s.failures))
+	}
+
+	header(cw, "gorcon_command_duration_seconds", "histogram", "Latency of commands in seconds.")
+	for _, addr := range addrs {
+		servers[addr].latency.write(cw, "gorcon_command_duration_seconds", addr)
+	}
+
+	header(cw, "gorcon_response_size_bytes", "histogram", "Size of command responses in bytes.")
+	for _, addr := range addrs {
+		servers[addr].size.write(cw, "gorcon_response_size_bytes", addr)
+	}
+
+	counters := []struct {
+		name  string
+		help  string
+		value func(s *server) uint64
+	}{
+		{"gorcon_continuation_packets_total", "Number of continuation packets of split responses.", func(s *server) uint64 { return s.continuations }},
+		{"gorcon_auth_failures_total", "Number of failed authentications.", func(s *server) uint64 { return s.authFailures }},
+		{"gorcon_dial_errors_total", "Number of failed dials.", func(s *server) uint64 { return s.dialErrors }},
+		{"gorcon_reconnects_total", "Number of reconnects.", func(s *server) uint64 { return s.reconnects }},
+	}
+	for _, m := range counters {
+		header(cw, m.name, "counter", m.help)
+		for _, addr := range addrs {
+			sample(cw, m.name, labels(addr), float64(m.value(servers[addr])))
+		}
+	}
+
+	if err := cw.w.Flush(); err != nil && cw.err == nil {
+		cw.err = err
+	}
+
+	return cw.n, cw.err
+}
+
+// snapshot copies the metrics of every server and returns their addresses in
+// order.
+func (c *Collector) snapshot() ([]string, map[string]*server) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	addrs := make([]string, 0, len(c.servers))
+	servers := make(map[string]*server, len(c.servers))
+	for addr, s := range c.servers {
+		addrs = append(addrs, addr)
+
+		cp := *s
+		cp.latency = s.latency.clone()
+		cp.size = s.size.clone()
+		servers[addr] = &cp
+	}
+	sort.Strings(addrs)
+
+	return addrs, servers
+}
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (h *histogram) clone() *histogram {
+	cp := *h
+	cp.counts = make([]uint64, len(h.counts))
+	copy(cp.counts, h.counts)
+
+	return &cp
+}
+
+func (h *histogram) observe(v float64) {
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+func (h *histogram) write(w io.Writer, name string, addr string) {
+	for i, b := range h.buckets {
+		sample(w, name+"_bucket", labels(addr, "le", formatFloat(b)), float64(h.counts[i]))
+	}
+	sample(w, name+"_bucket", labels(addr, "le", "+Inf"), float64(h.count))
+	sample(w, name+"_sum", labels(addr), h.sum)
+	sample(w, name+"_count", labels(addr), float64(h.count))
+}
+
+func header(w io.Writer, name string, typ string, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func sample(w io.Writer, name string, labels string, value float64) {
+	fmt.Fprintf(w, "%s{%s} %s\n", name, labels, formatFloat(value))
+}
+
+// labels formats the addr label followed by extra key/value pairs.
+func labels(addr string, kv ...string) string {
+	b := new(strings.Builder)
+	b.WriteString(`addr="`)
+	b.WriteString(escapeLabel(addr))
+	b.WriteByte('"')
+
+	for i := 0; i+1 < len(kv); i += 2 {
+		b.WriteByte(',')
+		b.WriteString(kv[i])
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(kv[i+1]))
+		b.WriteByte('"')
+	}
+
+	return b.String()
+}
+
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelReplacer.Replace(s)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedBuckets(buckets []float64) []float64 {
+	b := make([]float64, len(buckets))
+	copy(b, buckets)
+	sort.Float64s(b)
+
+	return b
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+
+	return n, err
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package metrics_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	rcon "github.com/Aton-Kish/gorcon"
+	"github.com/Aton-Kish/gorcon/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ rcon.Hooks = (*metrics.Collector)(nil)
+
+func TestCollector_WriteTo(t *testing.T) {
+	c := metrics.NewCollector(
+		metrics.WithLatencyBuckets(0.5, 0.1),
+		metrics.WithSizeBuckets(1024),
+	)
+
+	c.CommandDone("localhost:25575", 50*time.Millisecond, 8, 0, nil)
+	c.CommandDone("localhost:25575", 250*time.Millisecond, 10000, 2, nil)
+	c.CommandDone("localhost:25575", time.Second, 0, 0, errors.New("timeout"))
+	c.AuthFailed("localhost:25575", errors.New("unauthorized"))
+	c.DialFailed(`mc"1":25575`, errors.New("refused"))
+	c.Reconnected("localhost:25575")
+
+	expected := strings.Join([]string{
+		`# HELP gorcon_commands_total Number of commands sent.`,
+		`# TYPE gorcon_commands_total counter`,
+		`gorcon_commands_total{addr="localhost:25575",result="success"} 2`,
+		`gorcon_commands_total{addr="localhost:25575",result="error"} 1`,
+		`gorcon_commands_total{addr="mc\"1\":25575",result="success"} 0`,
+		`gorcon_commands_total{addr="mc\"1\":25575",result="error"} 0`,
+		`# HELP gorcon_command_duration_seconds Latency of commands in seconds.`,
+		`# TYPE gorcon_command_duration_seconds histogram`,
+		`gorcon_command_duration_seconds_bucket{addr="localhost:25575",le="0.1"} 1`,
+		`gorcon_command_duration_seconds_bucket{addr="localhost:25575",le="0.5"} 2`,
+		`gorcon_command_duration_seconds_bucket{addr="localhost:25575",le="+Inf"} 3`,
+		`gorcon_command_duration_seconds_sum{addr="localhost:25575"} 1.3`,
+		`gorcon_command_duration_seconds_count{addr="localhost:25575"} 3`,
+		`gorcon_command_duration_seconds_bucket{addr="mc\"1\":25575",le="0.1"} 0`,
+		`gorcon_command_duration_seconds_bucket{addr="mc\"1\":25575",le="0.5"} 0`,
+		`gorcon_command_duration_seconds_bucket{addr="mc\"1\":25575",le="+Inf"} 0`,
+		`gorcon_command_duration_seconds_sum{addr="mc\"1\":25575"} 0`,
+		`gorcon_command_duration_seconds_count{addr="mc\"1\":25575"} 0`,
+		`# HELP gorcon_response_size_bytes Size of command responses in bytes.`,
+		`# TYPE gorcon_response_size_bytes histogram`,
+		`gorcon_response_size_bytes_bucket{addr="localhost:25575",le="1024"} 1`,
+		`gorcon_response_size_bytes_bucket{addr="localhost:25575",le="+Inf"} 2`,
+		`gorcon_response_size_bytes_sum{addr="localhost:25575"} 10008`,
+		`gorcon_response_size_bytes_count{addr="localhost:25575"} 2`,
+		`gorcon_response_size_bytes_bucket{addr="mc\"1\":25575",le="1024"} 0`,
+		`gorcon_response_size_bytes_bucket{addr="mc\"1\":25575",le="+Inf"} 0`,
+		`gorcon_response_size_bytes_sum{addr="mc\"1\":25575"} 0`,
+		`gorcon_response_size_bytes_count{addr="mc\"1\":25575"} 0`,
+		`# HELP gorcon_continuation_packets_total Number of continuation packets of split responses.`,
+		`# TYPE gorcon_continuation_packets_total counter`,
+		`gorcon_continuation_packets_total{addr="localhost:25575"} 2`,
+		`gorcon_continuation_packets_total{addr="mc\"1\":25575"} 0`,
+		`# HELP gorcon_auth_failures_total Number of failed authentications.`,
+		`# TYPE gorcon_auth_failures_total counter`,
+		`gorcon_auth_failures_total{addr="localhost:25575"} 1`,
+		`gorcon_auth_failures_total{addr="mc\"1\":25575"} 0`,
+		`# HELP gorcon_dial_errors_total Number of failed dials.`,
+		`# TYPE gorcon_dial_errors_total counter`,
+		`gorcon_dial_errors_total{addr="localhost:25575"} 0`,
+		`gorcon_dial_errors_total{addr="mc\"1\":25575"} 1`,
+		`# HELP gorcon_reconnects_total Number of reconnects.`,
+		`# TYPE gorcon_reconnects_total counter`,
+		`gorcon_reconnects_total{addr="localhost:25575"} 1`,
+		`gorcon_reconnects_total{addr="mc\"1\":25575"} 0`,
+		``,
+	}, "\n")
+
+	buf := new(strings.Builder)
+	n, err := c.WriteTo(buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(expected)), n)
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestCollector_ServeHTTP(t *testing.T) {
+	c := metrics.NewCollector()
+	c.Reconnected("localhost:25575")
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `gorcon_reconnects_total{addr="localhost:25575"} 1`)
+}
+
+// blockingWriter blocks every write until release is closed.
+type blockingWriter struct {
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.started) })
+	<-w.release
+
+	return len(p), nil
+}
+
+func TestCollector_WriteTo_slowWriter(t *testing.T) {
+	c := metrics.NewCollector()
+	c.CommandDone("localhost:25575", 3*time.Millisecond, 100, 0, nil)
+
+	w := &blockingWriter{started: make(chan struct{}), release: make(chan struct{})}
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		_, _ = c.WriteTo(w)
+	}()
+
+	<-w.started
+
+	// NOTE: hooks must not wait for the writer
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.CommandDone("localhost:25575", 3*time.Millisecond, 100, 0, nil)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("CommandDone blocked by WriteTo")
+	}
+
+	close(w.release)
+	<-written
+	<-done
+}
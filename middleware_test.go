@@ -0,0 +1,114 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package rcon
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/gorcon/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain(t *testing.T) {
+	order := []string{}
+
+	mw := func(name string) Middleware {
+		return func(next CommandFunc) CommandFunc {
+			return func(ctx context.Context, command string) (string, error) {
+				order = append(order, name+" before")
+				res, err := next(ctx, command+" "+name)
+				order = append(order, name+" after")
+				return res, err
+			}
+		}
+	}
+
+	echo := func(ctx context.Context, command string) (string, error) {
+		return command, nil
+	}
+
+	res, err := Chain(mw("a"), mw("b"))(echo)(context.Background(), "cmd")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cmd a b", res)
+	assert.Equal(t, []string{"a before", "b before", "b after", "a after"}, order)
+}
+
+func TestWithMiddleware(t *testing.T) {
+	errBlocked := errors.New("blocked")
+
+	block := func(next CommandFunc) CommandFunc {
+		return func(ctx context.Context, command string) (string, error) {
+			if strings.HasPrefix(command, "/stop") {
+				return "", errBlocked
+			}
+
+			return next(ctx, command)
+		}
+	}
+
+	rewrite := func(next CommandFunc) CommandFunc {
+		return func(ctx context.Context, command string) (string, error) {
+			res, err := next(ctx, strings.TrimPrefix(command, "/"))
+			return strings.ToUpper(res), err
+		}
+	}
+
+	srv, clt := pipe(WithMiddleware(block, rewrite))
+	defer clt.Close()
+
+	errCh := make(chan error, 1)
+	defer close(errCh)
+
+	go func() {
+		defer srv.Close()
+
+		req := new(protocol.Packet)
+		if err := srv.dec.Decode(req); err != nil {
+			errCh <- err
+			return
+		}
+
+		// NOTE: echo the command
+		res := protocol.NewPacket(req.RequestId, protocol.CommandResponseType, req.Payload)
+		if err := srv.enc.Encode(res); err != nil {
+			errCh <- err
+			return
+		}
+
+		errCh <- nil
+	}()
+
+	_, err := clt.Command("/stop")
+	assert.ErrorIs(t, err, errBlocked)
+
+	res, err := clt.Command("/seed")
+	assert.NoError(t, err)
+	assert.Equal(t, "SEED", res)
+	assert.NoError(t, <-errCh)
+
+	assert.Equal(t, Stats{Commands: 1, PacketsSent: 1, PacketsReceived: 1}, clt.Stats())
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package minecraft
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GameMode is a game mode of gamemode.
+type GameMode string
+
+const (
+	Survival  GameMode = "survival"
+	Creative  GameMode = "creative"
+	Adventure GameMode = "adventure"
+	Spectator GameMode = "spectator"
+)
+
+// BlockMode is how setblock and fill handle existing blocks. The empty mode is
+// the server default, replace.
+type BlockMode string
+
+const (
+	Replace BlockMode = "replace"
+	Destroy BlockMode = "destroy"
+	Keep    BlockMode = "keep"
+	// Hollow is only valid for fill.
+	Hollow BlockMode = "hollow"
+	// Outline is only valid for fill.
+	Outline BlockMode = "outline"
+)
+
+func build(args ...string) Cmd {
+	return Cmd("/" + strings.Join(args, " "))
+}
+
+func withMode(args []string, mode BlockMode) Cmd {
+	if mode != "" {
+		args = append(args, string(mode))
+	}
+
+	return build(args...)
+}
+
+// Give gives count items to the targets. The item may carry components or
+// NBT, such as `diamond_sword[damage=1]`.
+func Give(targets, item string, count int) Cmd {
+	return build("give", targets, resource(item), strconv.Itoa(count))
+}
+
+// Teleport teleports the targets to pos.
+func Teleport(targets string, pos Pos) Cmd {
+	return build("tp", targets, pos.String())
+}
+
+// TeleportTo teleports the targets to the destination entity.
+func TeleportTo(targets, destination string) Cmd {
+	return build("tp", targets, destination)
+}
+
+// Summon summons an entity at pos. The snbt, as built by snbt.Marshal, may be
+// empty.
+func Summon(entity string, pos Pos, snbt string) Cmd {
+	args := []string{"summon", resource(entity), pos.String()}
+	if snbt != "" {
+		args = append(args, snbt)
+	}
+
+	return build(args...)
+}
+
+// SetBlock places a block, which may carry block states and NBT, at pos.
+func SetBlock(pos Pos, block string, mode BlockMode) Cmd {
+	return withMode([]string{"setblock", pos.String(), resource(block)}, mode)
+}
+
+// Fill fills the region between from and to with a block.
+func Fill(from, to Pos, block string, mode BlockMode) Cmd {
+	return withMode([]string{"fill", from.String(), to.String(), resource(block)}, mode)
+}
+
+// SetGameMode sets the game mode of the targets. Empty targets mean the
+// executor.
+func SetGameMode(mode GameMode, targets string) Cmd {
+	if targets == "" {
+		return build("gamemode", string(mode))
+	}
+
+	return build("gamemode", string(mode), targets)
+}
+
+// EffectGive gives an effect to the targets for the given seconds. The
+// amplifier is zero-based.
+func EffectGive(targets, effect string, seconds, amplifier int, hideParticles bool) Cmd {
+	return build("effect", "give", targets, resource(effect), strconv.Itoa(seconds), strconv.Itoa(amplifier), strconv.FormatBool(hideParticles))
+}
+
+// EffectClear clears an effect from the targets. An empty effect clears all.
+func EffectClear(targets, effect string) Cmd {
+	if effect == "" {
+		return build("effect", "clear", targets)
+	}
+
+	return build("effect", "clear", targets, resource(effect))
+}
+
+// Kill kills the targets.
+func Kill(targets string) Cmd {
+	return build("kill", targets)
+}
+
+// Say broadcasts a message. Line breaks are replaced with spaces, since a
+// command is a single line.
+func Say(message string) Cmd {
+	return build("say", line(message))
+}
+
+// Tellraw sends a JSON text component, such as a Text, to the targets. A
+// string is sent as plain text; any other value is encoded as JSON.
+func Tellraw(targets string, text any) Cmd {
+	return build("tellraw", targets, component(text))
+}
+
+// Title shows a title to the targets.
+func Title(targets string, text any) Cmd {
+	return build("title", targets, "title", component(text))
+}
+
+// Subtitle sets the subtitle shown with the next title.
+func Subtitle(targets string, text any) Cmd {
+	return build("title", targets, "subtitle", component(text))
+}
+
+// ActionBar shows text above the hotbar of the targets.
+func ActionBar(targets string, text any) Cmd {
+	return build("title", targets, "actionbar", component(text))
+}
+
+// TitleTimes sets the fade-in, stay and fade-out durations, in ticks.
+func TitleTimes(targets string, fadeIn, stay, fadeOut int) Cmd {
+	return build("title", targets, "times", strconv.Itoa(fadeIn), strconv.Itoa(stay), strconv.Itoa(fadeOut))
+}
+
+// TitleClear clears the title of the targets.
+func TitleClear(targets string) Cmd {
+	return build("title", targets, "clear")
+}
+
+// TitleReset resets the title options of the targets.
+func TitleReset(targets string) Cmd {
+	return build("title", targets, "reset")
+}
+
+// BossbarAdd creates a boss bar with a name, which may be a Text.
+func BossbarAdd(id string, name any) Cmd {
+	return build("bossbar", "add", resource(id), component(name))
+}
+
+// BossbarSetName renames a boss bar.
+func BossbarSetName(id string, name any) Cmd {
+	return build("bossbar", "set", resource(id), "name", component(name))
+}
+
+// component encodes a JSON text component on a single line.
+func component(text any) string {
+	b, err := json.Marshal(text)
+	if err != nil {
+		// NOTE: fall back to plain text
+		b, _ = json.Marshal(fmt.Sprint(text))
+	}
+
+	return string(b)
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package minecraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommands(t *testing.T) {
+	cases := []struct {
+		name     string
+		cmd      Cmd
+		expected string
+	}{
+		{
+			name:     "positive case: give",
+			cmd:      Give("jeb_", "dirt", 1),
+			expected: "/give jeb_ minecraft:dirt 1",
+		},
+		{
+			name:     "positive case: teleport",
+			cmd:      Teleport("@a", At(0, 64, 0)),
+			expected: "/tp @a 0 64 0",
+		},
+		{
+			name:     "positive case: teleport to",
+			cmd:      TeleportTo("@a", "jeb_"),
+			expected: "/tp @a jeb_",
+		},
+		{
+			name:     "positive case: summon",
+			cmd:      Summon("zombie", Here(), ""),
+			expected: "/summon minecraft:zombie ~ ~ ~",
+		},
+		{
+			name:     "positive case: summon with nbt",
+			cmd:      Summon("zombie", Here(), "{NoAI:1b}"),
+			expected: "/summon minecraft:zombie ~ ~ ~ {NoAI:1b}",
+		},
+		{
+			name:     "positive case: setblock",
+			cmd:      SetBlock(At(1, 2, 3), "stone", ""),
+			expected: "/setblock 1 2 3 minecraft:stone",
+		},
+		{
+			name:     "positive case: setblock with mode",
+			cmd:      SetBlock(Here(), "oak_stairs[facing=east]", Destroy),
+			expected: "/setblock ~ ~ ~ minecraft:oak_stairs[facing=east] destroy",
+		},
+		{
+			name:     "positive case: fill",
+			cmd:      Fill(At(0, 0, 0), At(9, 9, 9), "glass", Hollow),
+			expected: "/fill 0 0 0 9 9 9 minecraft:glass hollow",
+		},
+		{
+			name:     "positive case: gamemode",
+			cmd:      SetGameMode(Creative, "jeb_"),
+			expected: "/gamemode creative jeb_",
+		},
+		{
+			name:     "positive case: gamemode of executor",
+			cmd:      SetGameMode(Spectator, ""),
+			expected: "/gamemode spectator",
+		},
+		{
+			name:     "positive case: effect give",
+			cmd:      EffectGive("@p", "speed", 30, 1, true),
+			expected: "/effect give @p minecraft:speed 30 1 true",
+		},
+		{
+			name:     "positive case: effect clear",
+			cmd:      EffectClear("@p", ""),
+			expected: "/effect clear @p",
+		},
+		{
+			name:     "positive case: effect clear one",
+			cmd:      EffectClear("@p", "speed"),
+			expected: "/effect clear @p minecraft:speed",
+		},
+		{
+			name:     "positive case: kill",
+			cmd:      Kill("@e[type=minecraft:item]"),
+			expected: "/kill @e[type=minecraft:item]",
+		},
+		{
+			name:     "positive case: say",
+			cmd:      Say("Server restarts\nin 5 minutes"),
+			expected: "/say Server restarts in 5 minutes",
+		},
+		{
+			name:     "positive case: say keeps spacing",
+			cmd:      Say("  1.  Spawn\r\n2.  Nether  "),
+			expected: "/say   1.  Spawn 2.  Nether  ",
+		},
+		{
+			name:     "positive case: tellraw",
+			cmd:      Tellraw("@a", `He said "hi"`),
+			expected: `/tellraw @a "He said \"hi\""`,
+		},
+		{
+			name:     "positive case: tellraw component",
+			cmd:      Tellraw("@a", map[string]any{"text": "hi", "color": "red"}),
+			expected: `/tellraw @a {"color":"red","text":"hi"}`,
+		},
+		{
+			name:     "positive case: tellraw unsupported value",
+			cmd:      Tellraw("@a", complex(1, 2)),
+			expected: `/tellraw @a "(1+2i)"`,
+		},
+		{
+			name:     "positive case: title",
+			cmd:      Title("@a", "Welcome"),
+			expected: `/title @a title "Welcome"`,
+		},
+		{
+			name:     "positive case: subtitle",
+			cmd:      Subtitle("@a", "to the server"),
+			expected: `/title @a subtitle "to the server"`,
+		},
+		{
+			name:     "positive case: actionbar",
+			cmd:      ActionBar("@a", "line\nbreak"),
+			expected: `/title @a actionbar "line\nbreak"`,
+		},
+		{
+			name:     "positive case: title text",
+			cmd:      Title("@a", NewText("Welcome").WithColor(Gold)),
+			expected: `/title @a title {"text":"Welcome","color":"gold"}`,
+		},
+		{
+			name:     "positive case: bossbar add",
+			cmd:      BossbarAdd("event", NewText("Event")),
+			expected: `/bossbar add minecraft:event {"text":"Event"}`,
+		},
+		{
+			name:     "positive case: bossbar set name",
+			cmd:      BossbarSetName("custom:event", "Ends soon"),
+			expected: `/bossbar set custom:event name "Ends soon"`,
+		},
+		{
+			name:     "positive case: title times",
+			cmd:      TitleTimes("@a", 10, 70, 20),
+			expected: "/title @a times 10 70 20",
+		},
+		{
+			name:     "positive case: title clear",
+			cmd:      TitleClear("@a"),
+			expected: "/title @a clear",
+		},
+		{
+			name:     "positive case: title reset",
+			cmd:      TitleReset("@a"),
+			expected: "/title @a reset",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.cmd.String())
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package minecraft
+
+import (
+	"strconv"
+)
+
+// CoordKind is the notation of a Coord.
+type CoordKind int
+
+const (
+	// Absolute is a world coordinate, such as 64.
+	Absolute CoordKind = iota
+	// Relative is an offset from the executor, such as ~1.
+	Relative
+	// Local is an offset along the executor's rotation, such as ^1.
+	Local
+)
+
+// Coord is a single coordinate of a position.
+type Coord struct {
+	Kind  CoordKind
+	Value float64
+}
+
+// Abs returns an absolute coordinate.
+func Abs(v float64) Coord {
+	return Coord{Kind: Absolute, Value: v}
+}
+
+// Rel returns a relative coordinate.
+func Rel(v float64) Coord {
+	return Coord{Kind: Relative, Value: v}
+}
+
+// Loc returns a local coordinate.
+func Loc(v float64) Coord {
+	return Coord{Kind: Local, Value: v}
+}
+
+func (c Coord) String() string {
+	v := strconv.FormatFloat(c.Value, 'f', -1, 64)
+
+	switch c.Kind {
+	case Relative:
+		if c.Value == 0 {
+			return "~"
+		}
+		return "~" + v
+	case Local:
+		if c.Value == 0 {
+			return "^"
+		}
+		return "^" + v
+	default:
+		return v
+	}
+}
+
+// Pos is a position of three coordinates.
+type Pos struct {
+	X, Y, Z Coord
+}
+
+// At returns an absolute position.
+func At(x, y, z float64) Pos {
+	return Pos{X: Abs(x), Y: Abs(y), Z: Abs(z)}
+}
+
+// Here returns the position of the executor, `~ ~ ~`.
+func Here() Pos {
+	return Pos{X: Rel(0), Y: Rel(0), Z: Rel(0)}
+}
+
+func (p Pos) String() string {
+	return p.X.String() + " " + p.Y.String() + " " + p.Z.String()
+}
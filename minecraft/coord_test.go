@@ -0,0 +1,64 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package minecraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPos_String(t *testing.T) {
+	cases := []struct {
+		name     string
+		pos      Pos
+		expected string
+	}{
+		{
+			name:     "positive case: absolute",
+			pos:      At(1, -64, 2.5),
+			expected: "1 -64 2.5",
+		},
+		{
+			name:     "positive case: here",
+			pos:      Here(),
+			expected: "~ ~ ~",
+		},
+		{
+			name:     "positive case: mixed",
+			pos:      Pos{X: Rel(1), Y: Abs(70), Z: Rel(-0.5)},
+			expected: "~1 70 ~-0.5",
+		},
+		{
+			name:     "positive case: local",
+			pos:      Pos{X: Loc(0), Y: Loc(0), Z: Loc(3)},
+			expected: "^ ^ ^3",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.pos.String())
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package minecraft
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	nbt "github.com/Aton-Kish/gonbt"
+	"github.com/Aton-Kish/gorcon/snbt"
+)
+
+var (
+	// NOTE: match the first occurrence of the phrase, as the data may quote it
+	dataQueryRe = regexp.MustCompile(`(?s)^(?:.*? has the following (?:entity|block) data|Storage \S+ has the following contents): (.*)$`)
+	dataScaleRe = regexp.MustCompile(`(?s)^.*? after scale factor of \S+ is (-?\d+)$`)
+)
+
+// DataGetEntity returns `/data get entity`. An empty path gets all data.
+func DataGetEntity(target, path string) Cmd {
+	return dataGet("entity", target, path)
+}
+
+// DataGetBlock returns `/data get block`. An empty path gets all data.
+func DataGetBlock(pos Pos, path string) Cmd {
+	return dataGet("block", pos.String(), path)
+}
+
+// DataGetStorage returns `/data get storage`. An empty path gets all data.
+func DataGetStorage(id, path string) Cmd {
+	return dataGet("storage", resource(id), path)
+}
+
+func dataGet(kind, target, path string) Cmd {
+	if path == "" {
+		return build("data", "get", kind, target)
+	}
+
+	return build("data", "get", kind, target, path)
+}
+
+// ParseData returns the SNBT of the output of `/data get`. It handles the
+// outputs of entities, blocks and storages, as well as scaled numeric results.
+func ParseData(res string) (string, error) {
+	s := strings.TrimSpace(res)
+
+	if g := dataQueryRe.FindStringSubmatch(s); g != nil {
+		return g[1], nil
+	}
+
+	if g := dataScaleRe.FindStringSubmatch(s); g != nil {
+		return g[1], nil
+	}
+
+	return "", &UnexpectedOutputError{Command: "data get", Output: res}
+}
+
+// GetEntityData gets the data of an entity, selected by a selector or UUID,
+// at path.
+func GetEntityData(ctx context.Context, c Commander, target, path string) (nbt.Tag, error) {
+	return getData(ctx, c, DataGetEntity(target, path))
+}
+
+// GetBlockData gets the data of the block entity at pos at path.
+func GetBlockData(ctx context.Context, c Commander, pos Pos, path string) (nbt.Tag, error) {
+	return getData(ctx, c, DataGetBlock(pos, path))
+}
+
+// GetStorage gets the data of a command storage at path.
+func GetStorage(ctx context.Context, c Commander, id, path string) (nbt.Tag, error) {
+	return getData(ctx, c, DataGetStorage(id, path))
+}
+
+// GetEntityDataInto is like GetEntityData, but unmarshals the data into v
+// with snbt.Unmarshal.
+func GetEntityDataInto(ctx context.Context, c Commander, target, path string, v any) error {
+	return getDataInto(ctx, c, DataGetEntity(target, path), v)
+}
+
+// GetBlockDataInto is like GetBlockData, but unmarshals the data into v with
+// snbt.Unmarshal.
+func GetBlockDataInto(ctx context.Context, c Commander, pos Pos, path string, v any) error {
+	return getDataInto(ctx, c, DataGetBlock(pos, path), v)
+}
+
+// GetStorageInto is like GetStorage, but unmarshals the data into v with
+// snbt.Unmarshal.
+func GetStorageInto(ctx context.Context, c Commander, id, path string, v any) error {
+	return getDataInto(ctx, c, DataGetStorage(id, path), v)
+}
+
+func getData(ctx context.Context, c Commander, cmd Cmd) (nbt.Tag, error) {
+	res, data, err := runData(ctx, c, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := nbt.Parse(data)
+	if err != nil {
+		return nil, &UnexpectedOutputError{Command: "data get", Output: res}
+	}
+
+	return tag, nil
+}
+
+func getDataInto(ctx context.Context, c Commander, cmd Cmd, v any) error {
+	_, data, err := runData(ctx, c, cmd)
+	if err != nil {
+		return err
+	}
+
+	return snbt.Unmarshal(data, v)
+}
+
+// runData runs a `/data get` command and returns the response and its SNBT.
+func runData(ctx context.Context, c Commander, cmd Cmd) (string, string, error) {
+	res, err := Run(ctx, c, cmd)
+	if err != nil {
+		return "", "", err
+	}
+
+	data, err := ParseData(res)
+	if err != nil {
+		return "", "", err
+	}
+
+	return res, data, nil
+}
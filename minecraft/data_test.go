@@ -0,0 +1,210 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package minecraft
+
+import (
+	"context"
+	"testing"
+
+	nbt "github.com/Aton-Kish/gonbt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseData(t *testing.T) {
+	cases := []struct {
+		name     string
+		res      string
+		expected string
+	}{
+		{
+			name:     "positive case: entity",
+			res:      "jeb_ has the following entity data: {Health: 20.0f}",
+			expected: "{Health: 20.0f}",
+		},
+		{
+			name:     "positive case: entity with path",
+			res:      "jeb_ has the following entity data: [0.5d, 64.0d, 0.5d]",
+			expected: "[0.5d, 64.0d, 0.5d]",
+		},
+		{
+			name:     "positive case: entity with numeric path",
+			res:      "jeb_ has the following entity data: 20.0f",
+			expected: "20.0f",
+		},
+		{
+			name:     "positive case: block",
+			res:      "0, -60, 0 has the following block data: {Items: []}",
+			expected: "{Items: []}",
+		},
+		{
+			name:     "positive case: storage",
+			res:      "Storage minecraft:test has the following contents: {a: 1b}",
+			expected: "{a: 1b}",
+		},
+		{
+			name:     "positive case: data quoting the phrase",
+			res:      `jeb_ has the following entity data: {CustomName: "Bob has the following entity data: lol"}`,
+			expected: `{CustomName: "Bob has the following entity data: lol"}`,
+		},
+		{
+			name:     "positive case: data quoting the scale phrase",
+			res:      `jeb_ has the following entity data: {CustomName: "Health after scale factor of 1.00 is 20"}`,
+			expected: `{CustomName: "Health after scale factor of 1.00 is 20"}`,
+		},
+		{
+			name:     "positive case: scaled entity",
+			res:      "Health on jeb_ after scale factor of 10.00 is 200",
+			expected: "200",
+		},
+		{
+			name:     "positive case: scaled block",
+			res:      "Items[0].Count on block 0, -60, 0 after scale factor of 1.00 is 64",
+			expected: "64",
+		},
+		{
+			name:     "positive case: scaled storage",
+			res:      "a in storage minecraft:test after scale factor of 1.00 is -1",
+			expected: "-1",
+		},
+		{
+			name:     "negative case: no entity",
+			res:      "No entity was found",
+			expected: "",
+		},
+		{
+			name:     "negative case: no elements",
+			res:      "Found no elements matching Foo",
+			expected: "",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseData(tt.res)
+
+			if tt.expected == "" {
+				assert.IsType(t, &UnexpectedOutputError{}, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestDataGet(t *testing.T) {
+	assert.Equal(t, Cmd("/data get entity @p"), DataGetEntity("@p", ""))
+	assert.Equal(t, Cmd("/data get entity @p Pos"), DataGetEntity("@p", "Pos"))
+	assert.Equal(t, Cmd("/data get block 0 -60 0 Items"), DataGetBlock(At(0, -60, 0), "Items"))
+	assert.Equal(t, Cmd("/data get storage minecraft:test a.b"), DataGetStorage("test", "a.b"))
+}
+
+func mustParseNBT(t *testing.T, s string) nbt.Tag {
+	t.Helper()
+
+	tag, err := nbt.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tag
+}
+
+func TestGetEntityData(t *testing.T) {
+	m := &mockCommander{responses: map[string]string{
+		"/data get entity jeb_ Health": "jeb_ has the following entity data: 20.0f",
+		"/data get entity Notch":       "No entity was found",
+		"/data get entity Bob":         `Bob has the following entity data: {CustomName: "Bob has the following entity data: lol", Health: 20.0f}`,
+		"/data get entity Grumm":       "Grumm has the following entity data: {Health: 20.0f",
+	}}
+
+	actual, err := GetEntityData(context.Background(), m, "jeb_", "Health")
+	assert.NoError(t, err)
+	assert.Equal(t, mustParseNBT(t, "20.0f"), actual)
+
+	actual, err = GetEntityData(context.Background(), m, "Bob", "")
+	assert.NoError(t, err)
+	assert.Equal(t, mustParseNBT(t, `{CustomName: "Bob has the following entity data: lol", Health: 20.0f}`), actual)
+
+	_, err = GetEntityData(context.Background(), m, "Notch", "")
+	assert.IsType(t, &UnexpectedOutputError{}, err)
+
+	_, err = GetEntityData(context.Background(), m, "Grumm", "")
+	assert.IsType(t, &UnexpectedOutputError{}, err)
+
+	var v struct{}
+	err = GetEntityDataInto(context.Background(), m, "Steve", "", &v)
+	assert.EqualError(t, err, "unknown command")
+}
+
+func TestGetEntityDataInto(t *testing.T) {
+	m := &mockCommander{responses: map[string]string{
+		"/data get entity jeb_": `jeb_ has the following entity data: {Pos: [0.5d, 64.0d, 0.5d], Health: 20.0f, ` +
+			`UUID: [I; -2059632401, 1010256381, -1438018677, 1732958950], Inventory: [{Slot: 0b, id: "minecraft:dirt", count: 64}]}`,
+	}}
+
+	var p struct {
+		Pos       [3]float64 `nbt:"Pos"`
+		Health    float32    `nbt:"Health"`
+		UUID      string     `nbt:"UUID"`
+		Inventory []struct {
+			Slot  int8   `nbt:"Slot"`
+			ID    string `nbt:"id"`
+			Count int    `nbt:"count"`
+		} `nbt:"Inventory"`
+	}
+	err := GetEntityDataInto(context.Background(), m, "jeb_", "", &p)
+
+	assert.NoError(t, err)
+	assert.Equal(t, [3]float64{0.5, 64, 0.5}, p.Pos)
+	assert.Equal(t, float32(20), p.Health)
+	assert.Equal(t, "853c80ef-3c37-49fd-aa49-938b674adae6", p.UUID)
+	if assert.Len(t, p.Inventory, 1) {
+		assert.Equal(t, "minecraft:dirt", p.Inventory[0].ID)
+		assert.Equal(t, 64, p.Inventory[0].Count)
+	}
+}
+
+func TestGetBlockData(t *testing.T) {
+	m := &mockCommander{responses: map[string]string{
+		"/data get block 0 -60 0": "0, -60, 0 has the following block data: {Items: []}",
+	}}
+
+	actual, err := GetBlockData(context.Background(), m, At(0, -60, 0), "")
+	assert.NoError(t, err)
+	assert.Equal(t, mustParseNBT(t, "{Items: []}"), actual)
+
+	_, err = GetBlockData(context.Background(), m, At(1, -60, 0), "")
+	assert.Error(t, err)
+}
+
+func TestGetStorage(t *testing.T) {
+	m := &mockCommander{responses: map[string]string{
+		"/data get storage minecraft:test": "Storage minecraft:test has the following contents: {a: 1b, b: [I; 1, 2]}",
+	}}
+
+	actual, err := GetStorage(context.Background(), m, "minecraft:test", "")
+	assert.NoError(t, err)
+	assert.Equal(t, mustParseNBT(t, "{a: 1b, b: [I; 1, 2]}"), actual)
+	assert.Equal(t, []string{"/data get storage minecraft:test"}, m.commands)
+}
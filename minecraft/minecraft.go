@@ -0,0 +1,105 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package minecraft builds and runs vanilla Minecraft commands.
+package minecraft
+
+import (
+	"context"
+	"strings"
+)
+
+// Commander runs commands. It is satisfied by rcon.RCON and rcon.Client. If
+// it also has CommandContext, as rcon.Client does, commands are run with the
+// context; otherwise the context is only checked before sending.
+type Commander interface {
+	Command(command string) (string, error)
+}
+
+type contextCommander interface {
+	CommandContext(ctx context.Context, command string) (string, error)
+}
+
+// Cmd is a command built by this package.
+type Cmd string
+
+func (c Cmd) String() string {
+	return string(c)
+}
+
+// Run runs the command through c and returns the raw response.
+func Run(ctx context.Context, c Commander, cmd Cmd) (string, error) {
+	if cc, ok := c.(contextCommander); ok {
+		return cc.CommandContext(ctx, string(cmd))
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	return c.Command(string(cmd))
+}
+
+// Quote returns s as a string argument, quoting and escaping it unless it
+// only contains characters allowed in an unquoted string.
+func Quote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool { return !isUnquotedRune(r) }) < 0 {
+		return s
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+func isUnquotedRune(r rune) bool {
+	return '0' <= r && r <= '9' || 'A' <= r && r <= 'Z' || 'a' <= r && r <= 'z' || r == '_' || r == '-' || r == '.' || r == '+'
+}
+
+// resource adds the minecraft namespace to id if it has none. Block states,
+// components and NBT following the ID are kept as they are.
+func resource(id string) string {
+	name := id
+	if i := strings.IndexAny(id, "[{"); i >= 0 {
+		name = id[:i]
+	}
+
+	if name == "" || strings.Contains(name, ":") {
+		return id
+	}
+
+	return "minecraft:" + id
+}
+
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// line makes s fit on a single command line, replacing line breaks with
+// spaces and keeping any other whitespace.
+func line(s string) string {
+	return lineBreaks.Replace(s)
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	rcon "github.com/Aton-Kish/gorcon"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ Commander = rcon.RCON(nil)
+	_ Commander = rcon.Client(nil)
+)
+
+// plainCommander has no CommandContext, like an rcon.RCON of another
+// implementation.
+type plainCommander struct {
+	commands []string
+}
+
+func (m *plainCommander) Command(command string) (string, error) {
+	m.commands = append(m.commands, command)
+	return "Killed 2 entities", nil
+}
+
+// mockCommander records commands and answers from responses.
+type mockCommander struct {
+	commands  []string
+	responses map[string]string
+}
+
+func (m *mockCommander) Command(command string) (string, error) {
+	return m.CommandContext(context.Background(), command)
+}
+
+func (m *mockCommander) CommandContext(ctx context.Context, command string) (string, error) {
+	m.commands = append(m.commands, command)
+
+	res, ok := m.responses[command]
+	if !ok {
+		return "", errors.New("unknown command")
+	}
+
+	return res, nil
+}
+
+func TestRun(t *testing.T) {
+	m := &mockCommander{responses: map[string]string{"/kill @e[type=minecraft:zombie]": "Killed 2 entities"}}
+
+	res, err := Run(context.Background(), m, Kill("@e[type=minecraft:zombie]"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Killed 2 entities", res)
+	assert.Equal(t, []string{"/kill @e[type=minecraft:zombie]"}, m.commands)
+
+	_, err = Run(context.Background(), m, Say("hi"))
+	assert.Error(t, err)
+}
+
+func TestRun_withoutContext(t *testing.T) {
+	m := new(plainCommander)
+
+	res, err := Run(context.Background(), m, Kill("@e[type=minecraft:zombie]"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Killed 2 entities", res)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = Run(ctx, m, Kill("@e"))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"/kill @e[type=minecraft:zombie]"}, m.commands)
+}
+
+func TestQuote(t *testing.T) {
+	cases := []struct {
+		name     string
+		s        string
+		expected string
+	}{
+		{
+			name:     "positive case: unquoted",
+			s:        "jeb_",
+			expected: "jeb_",
+		},
+		{
+			name:     "positive case: space",
+			s:        "Hello World",
+			expected: `"Hello World"`,
+		},
+		{
+			name:     "positive case: escape",
+			s:        `say "hi" \o/`,
+			expected: `"say \"hi\" \\o/"`,
+		},
+		{
+			name:     "positive case: empty",
+			s:        "",
+			expected: `""`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Quote(tt.s))
+		})
+	}
+}
+
+func Test_resource(t *testing.T) {
+	cases := []struct {
+		name     string
+		id       string
+		expected string
+	}{
+		{
+			name:     "positive case",
+			id:       "dirt",
+			expected: "minecraft:dirt",
+		},
+		{
+			name:     "positive case: namespaced",
+			id:       "create:wrench",
+			expected: "create:wrench",
+		},
+		{
+			name:     "positive case: block state",
+			id:       "oak_stairs[facing=east]",
+			expected: "minecraft:oak_stairs[facing=east]",
+		},
+		{
+			name:     "positive case: nbt with colon",
+			id:       `chest{CustomName:'"a"'}`,
+			expected: `minecraft:chest{CustomName:'"a"'}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resource(tt.id))
+		})
+	}
+}
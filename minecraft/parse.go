@@ -0,0 +1,280 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package minecraft
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Player is an online player of PlayerList.
+type Player struct {
+	Name string
+	// UUID is only set by `/list uuids`.
+	UUID string
+}
+
+// PlayerList is the output of `/list`.
+type PlayerList struct {
+	Online  int
+	Max     int
+	Players []Player
+}
+
+// Difficulty is the output of `/difficulty`.
+type Difficulty string
+
+const (
+	Peaceful Difficulty = "peaceful"
+	Easy     Difficulty = "easy"
+	Normal   Difficulty = "normal"
+	Hard     Difficulty = "hard"
+)
+
+// GameRule is the output of `/gamerule`.
+type GameRule struct {
+	Name  string
+	Value string
+}
+
+// Bool returns the value of a boolean rule.
+func (r GameRule) Bool() (bool, error) {
+	return strconv.ParseBool(r.Value)
+}
+
+// Int returns the value of an integer rule.
+func (r GameRule) Int() (int, error) {
+	return strconv.Atoi(r.Value)
+}
+
+// Ban is an entry of `/banlist`.
+type Ban struct {
+	Target string
+	Source string
+	Reason string
+}
+
+// Score is the output of `/scoreboard players get`.
+type Score struct {
+	Target    string
+	Objective string
+	Value     int
+}
+
+var (
+	listRe        = regexp.MustCompile(`(?s)^There are (\d+)(?: of a max of |/)(\d+) players online:(.*)$`)
+	listPlayerRe  = regexp.MustCompile(`^(\S+)(?: \(([0-9a-fA-F-]{36})\))?$`)
+	seedRe        = regexp.MustCompile(`^Seed: \[(-?\d+)\]$`)
+	timeRe        = regexp.MustCompile(`^The time is (\d+)$`)
+	difficultyRe  = regexp.MustCompile(`^The difficulty (?:is|has been set to) (\w+)$`)
+	gameRuleRe    = regexp.MustCompile(`^Gamerule (\S+) is (?:currently|now) set to: (.*)$`)
+	whitelistRe   = regexp.MustCompile(`(?s)^There are (\d+) whitelisted players?(?:\(s\))?:(.*)$`)
+	banlistRe     = regexp.MustCompile(`^There are (\d+) bans?(?:\(s\))?:\s?`)
+	banRe         = regexp.MustCompile(`(\d{1,3}(?:\.\d{1,3}){3}|[0-9a-fA-F]*:[0-9a-fA-F:]+|[A-Za-z0-9_]{1,16}) was banned by (.*?): `)
+	scoreRe       = regexp.MustCompile(`^(\S+) has (-?\d+) \[(.*)\]$`)
+	worldBorderRe = regexp.MustCompile(`^The world border is currently ([0-9.]+) blocks?(?:\(s\))? wide$`)
+)
+
+// ParseList parses the output of `/list` or `/list uuids`.
+func ParseList(res string) (*PlayerList, error) {
+	g := listRe.FindStringSubmatch(strings.TrimSpace(res))
+	if g == nil {
+		return nil, &UnexpectedOutputError{Command: "list", Output: res}
+	}
+
+	online, _ := strconv.Atoi(g[1])
+	limit, _ := strconv.Atoi(g[2])
+
+	l := &PlayerList{Online: online, Max: limit, Players: []Player{}}
+	for _, s := range splitNames(g[3]) {
+		p := listPlayerRe.FindStringSubmatch(s)
+		if p == nil {
+			return nil, &UnexpectedOutputError{Command: "list", Output: res}
+		}
+
+		l.Players = append(l.Players, Player{Name: p[1], UUID: p[2]})
+	}
+
+	return l, nil
+}
+
+// ParseSeed parses the output of `/seed`.
+func ParseSeed(res string) (int64, error) {
+	g := seedRe.FindStringSubmatch(strings.TrimSpace(res))
+	if g == nil {
+		return 0, &UnexpectedOutputError{Command: "seed", Output: res}
+	}
+
+	seed, err := strconv.ParseInt(g[1], 10, 64)
+	if err != nil {
+		return 0, &UnexpectedOutputError{Command: "seed", Output: res}
+	}
+
+	return seed, nil
+}
+
+// ParseTime parses the output of `/time query daytime`, `gametime` or `day`.
+func ParseTime(res string) (int64, error) {
+	g := timeRe.FindStringSubmatch(strings.TrimSpace(res))
+	if g == nil {
+		return 0, &UnexpectedOutputError{Command: "time query", Output: res}
+	}
+
+	t, err := strconv.ParseInt(g[1], 10, 64)
+	if err != nil {
+		return 0, &UnexpectedOutputError{Command: "time query", Output: res}
+	}
+
+	return t, nil
+}
+
+// ParseDifficulty parses the output of `/difficulty`, with or without an
+// argument.
+func ParseDifficulty(res string) (Difficulty, error) {
+	g := difficultyRe.FindStringSubmatch(strings.TrimSpace(res))
+	if g == nil {
+		return "", &UnexpectedOutputError{Command: "difficulty", Output: res}
+	}
+
+	d := Difficulty(strings.ToLower(g[1]))
+	switch d {
+	case Peaceful, Easy, Normal, Hard:
+		return d, nil
+	default:
+		return "", &UnexpectedOutputError{Command: "difficulty", Output: res}
+	}
+}
+
+// ParseGameRule parses the output of `/gamerule`, with or without a value.
+func ParseGameRule(res string) (*GameRule, error) {
+	g := gameRuleRe.FindStringSubmatch(strings.TrimSpace(res))
+	if g == nil {
+		return nil, &UnexpectedOutputError{Command: "gamerule", Output: res}
+	}
+
+	return &GameRule{Name: g[1], Value: g[2]}, nil
+}
+
+// ParseWhitelist parses the output of `/whitelist list`.
+func ParseWhitelist(res string) ([]string, error) {
+	s := strings.TrimSpace(res)
+	if s == "There are no whitelisted players" {
+		return []string{}, nil
+	}
+
+	g := whitelistRe.FindStringSubmatch(s)
+	if g == nil {
+		return nil, &UnexpectedOutputError{Command: "whitelist list", Output: res}
+	}
+
+	names := splitNames(g[2])
+	if n, _ := strconv.Atoi(g[1]); n != len(names) {
+		return nil, &UnexpectedOutputError{Command: "whitelist list", Output: res}
+	}
+
+	return names, nil
+}
+
+// ParseBanList parses the output of `/banlist`.
+//
+// NOTE: the server may join the lines of the output without a separator, so
+// entries are split where the next `<target> was banned by` starts.
+func ParseBanList(res string) ([]Ban, error) {
+	s := strings.TrimSpace(res)
+	if s == "There are no bans" {
+		return []Ban{}, nil
+	}
+
+	h := banlistRe.FindStringSubmatch(s)
+	if h == nil {
+		return nil, &UnexpectedOutputError{Command: "banlist", Output: res}
+	}
+	s = s[len(h[0]):]
+
+	idx := banRe.FindAllStringSubmatchIndex(s, -1)
+	if len(idx) == 0 || idx[0][0] != 0 {
+		return nil, &UnexpectedOutputError{Command: "banlist", Output: res}
+	}
+
+	bans := make([]Ban, 0, len(idx))
+	for i, m := range idx {
+		end := len(s)
+		if i+1 < len(idx) {
+			end = idx[i+1][0]
+		}
+
+		bans = append(bans, Ban{
+			Target: s[m[2]:m[3]],
+			Source: s[m[4]:m[5]],
+			Reason: strings.TrimSpace(s[m[1]:end]),
+		})
+	}
+
+	if n, _ := strconv.Atoi(h[1]); n != len(bans) {
+		return nil, &UnexpectedOutputError{Command: "banlist", Output: res}
+	}
+
+	return bans, nil
+}
+
+// ParseScore parses the output of `/scoreboard players get`.
+func ParseScore(res string) (*Score, error) {
+	g := scoreRe.FindStringSubmatch(strings.TrimSpace(res))
+	if g == nil {
+		return nil, &UnexpectedOutputError{Command: "scoreboard players get", Output: res}
+	}
+
+	v, err := strconv.Atoi(g[2])
+	if err != nil {
+		return nil, &UnexpectedOutputError{Command: "scoreboard players get", Output: res}
+	}
+
+	return &Score{Target: g[1], Objective: g[3], Value: v}, nil
+}
+
+// ParseWorldBorder parses the output of `/worldborder get` into the width in
+// blocks.
+func ParseWorldBorder(res string) (float64, error) {
+	g := worldBorderRe.FindStringSubmatch(strings.TrimSpace(res))
+	if g == nil {
+		return 0, &UnexpectedOutputError{Command: "worldborder get", Output: res}
+	}
+
+	w, err := strconv.ParseFloat(g[1], 64)
+	if err != nil {
+		return 0, &UnexpectedOutputError{Command: "worldborder get", Output: res}
+	}
+
+	return w, nil
+}
+
+// splitNames splits a comma separated list of names.
+func splitNames(s string) []string {
+	names := []string{}
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,316 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package minecraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseList(t *testing.T) {
+	cases := []struct {
+		name     string
+		res      string
+		expected *PlayerList
+	}{
+		{
+			name:     "positive case",
+			res:      "There are 2 of a max of 20 players online: jeb_, Notch",
+			expected: &PlayerList{Online: 2, Max: 20, Players: []Player{{Name: "jeb_"}, {Name: "Notch"}}},
+		},
+		{
+			name:     "positive case: empty",
+			res:      "There are 0 of a max of 20 players online: ",
+			expected: &PlayerList{Online: 0, Max: 20, Players: []Player{}},
+		},
+		{
+			name:     "positive case: uuids",
+			res:      "There are 1 of a max of 20 players online: jeb_ (853c80ef-3c37-49fd-aa49-938b674adae6)",
+			expected: &PlayerList{Online: 1, Max: 20, Players: []Player{{Name: "jeb_", UUID: "853c80ef-3c37-49fd-aa49-938b674adae6"}}},
+		},
+		{
+			name:     "positive case: legacy",
+			res:      "There are 1/20 players online:\njeb_",
+			expected: &PlayerList{Online: 1, Max: 20, Players: []Player{{Name: "jeb_"}}},
+		},
+		{
+			name:     "negative case: unexpected output",
+			res:      "Unknown command",
+			expected: nil,
+		},
+		{
+			name:     "negative case: invalid name",
+			res:      "There are 1 of a max of 20 players online: jeb_ (x)",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseList(tt.res)
+
+			if tt.expected == nil {
+				assert.IsType(t, &UnexpectedOutputError{}, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestParseSeed(t *testing.T) {
+	cases := []struct {
+		name     string
+		res      string
+		expected int64
+		err      error
+	}{
+		{
+			name:     "positive case",
+			res:      "Seed: [-1234567890123]",
+			expected: -1234567890123,
+		},
+		{
+			name: "negative case: overflow",
+			res:  "Seed: [99999999999999999999]",
+			err:  &UnexpectedOutputError{},
+		},
+		{
+			name: "negative case: unexpected output",
+			res:  "Seed: 1",
+			err:  &UnexpectedOutputError{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseSeed(tt.res)
+
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.IsType(t, tt.err, err)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	actual, err := ParseTime("The time is 13000")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(13000), actual)
+
+	_, err = ParseTime("The time is noon")
+	assert.IsType(t, &UnexpectedOutputError{}, err)
+}
+
+func TestParseDifficulty(t *testing.T) {
+	cases := []struct {
+		name     string
+		res      string
+		expected Difficulty
+		err      error
+	}{
+		{
+			name:     "positive case: query",
+			res:      "The difficulty is Normal",
+			expected: Normal,
+		},
+		{
+			name:     "positive case: set",
+			res:      "The difficulty has been set to Peaceful",
+			expected: Peaceful,
+		},
+		{
+			name: "negative case: unknown difficulty",
+			res:  "The difficulty is Nightmare",
+			err:  &UnexpectedOutputError{},
+		},
+		{
+			name: "negative case: unexpected output",
+			res:  "The difficulty did not change; it is already set to Hard",
+			err:  &UnexpectedOutputError{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseDifficulty(tt.res)
+
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.IsType(t, tt.err, err)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestParseGameRule(t *testing.T) {
+	r, err := ParseGameRule("Gamerule keepInventory is currently set to: false")
+	assert.NoError(t, err)
+	assert.Equal(t, &GameRule{Name: "keepInventory", Value: "false"}, r)
+
+	b, err := r.Bool()
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	r, err = ParseGameRule("Gamerule randomTickSpeed is now set to: 3")
+	assert.NoError(t, err)
+
+	n, err := r.Int()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	_, err = ParseGameRule("Incorrect argument for command")
+	assert.IsType(t, &UnexpectedOutputError{}, err)
+}
+
+func TestParseWhitelist(t *testing.T) {
+	cases := []struct {
+		name     string
+		res      string
+		expected []string
+	}{
+		{
+			name:     "positive case",
+			res:      "There are 2 whitelisted player(s): jeb_, Notch",
+			expected: []string{"jeb_", "Notch"},
+		},
+		{
+			name:     "positive case: legacy",
+			res:      "There are 1 whitelisted players:\njeb_",
+			expected: []string{"jeb_"},
+		},
+		{
+			name:     "positive case: empty",
+			res:      "There are no whitelisted players",
+			expected: []string{},
+		},
+		{
+			name:     "negative case: count mismatch",
+			res:      "There are 3 whitelisted player(s): jeb_, Notch",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseWhitelist(tt.res)
+
+			if tt.expected == nil {
+				assert.IsType(t, &UnexpectedOutputError{}, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestParseBanList(t *testing.T) {
+	cases := []struct {
+		name     string
+		res      string
+		expected []Ban
+	}{
+		{
+			name: "positive case: joined lines",
+			res:  "There are 2 ban(s):jeb_ was banned by Server: Banned by an operator.192.168.0.1 was banned by Notch: griefing",
+			expected: []Ban{
+				{Target: "jeb_", Source: "Server", Reason: "Banned by an operator."},
+				{Target: "192.168.0.1", Source: "Notch", Reason: "griefing"},
+			},
+		},
+		{
+			name: "positive case: separate lines",
+			res:  "There are 2 ban(s):\njeb_ was banned by Rcon: spam\nNotch was banned by Server: Banned by an operator.",
+			expected: []Ban{
+				{Target: "jeb_", Source: "Rcon", Reason: "spam"},
+				{Target: "Notch", Source: "Server", Reason: "Banned by an operator."},
+			},
+		},
+		{
+			name:     "positive case: empty",
+			res:      "There are no bans",
+			expected: []Ban{},
+		},
+		{
+			name:     "negative case: count mismatch",
+			res:      "There are 2 ban(s):jeb_ was banned by Server: spam",
+			expected: nil,
+		},
+		{
+			name:     "negative case: unexpected output",
+			res:      "There are 1 ban(s):nobody",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseBanList(tt.res)
+
+			if tt.expected == nil {
+				assert.IsType(t, &UnexpectedOutputError{}, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestParseScore(t *testing.T) {
+	s, err := ParseScore("jeb_ has -5 [Deaths]")
+	assert.NoError(t, err)
+	assert.Equal(t, &Score{Target: "jeb_", Objective: "Deaths", Value: -5}, s)
+
+	_, err = ParseScore("Can't get value of Deaths for jeb_; none is set")
+	assert.IsType(t, &UnexpectedOutputError{}, err)
+}
+
+func TestParseWorldBorder(t *testing.T) {
+	w, err := ParseWorldBorder("The world border is currently 59999968 block(s) wide")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(59999968), w)
+
+	w, err = ParseWorldBorder("The world border is currently 1 block wide")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), w)
+
+	_, err = ParseWorldBorder("The world border is currently wide")
+	assert.IsType(t, &UnexpectedOutputError{}, err)
+}
+
+func TestUnexpectedOutputError_Error(t *testing.T) {
+	err := &UnexpectedOutputError{Command: "seed", Output: "x"}
+	assert.Equal(t, `minecraft seed: unexpected output: "x"`, err.Error())
+
+	var nilErr *UnexpectedOutputError
+	assert.Equal(t, "<nil>", nilErr.Error())
+}
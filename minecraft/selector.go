@@ -0,0 +1,593 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package minecraft
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Aton-Kish/gorcon/snbt"
+)
+
+// SelectorError is returned when a selector is invalid.
+type SelectorError struct {
+	Selector string
+	Msg      string
+}
+
+func (e *SelectorError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("minecraft selector %q: %s", e.Selector, e.Msg)
+}
+
+// Sort is the order of entities of a selector with a limit.
+type Sort string
+
+const (
+	SortNearest   Sort = "nearest"
+	SortFurthest  Sort = "furthest"
+	SortRandom    Sort = "random"
+	SortArbitrary Sort = "arbitrary"
+)
+
+// Range is an inclusive range of a selector argument, such as `..10`. A nil
+// bound is unbounded.
+type Range struct {
+	Min *float64
+	Max *float64
+}
+
+// Exactly returns the range of the single value v.
+func Exactly(v float64) Range {
+	return Range{Min: &v, Max: &v}
+}
+
+// AtLeast returns the range of v and above.
+func AtLeast(v float64) Range {
+	return Range{Min: &v}
+}
+
+// AtMost returns the range of v and below.
+func AtMost(v float64) Range {
+	return Range{Max: &v}
+}
+
+// Between returns the range from lo to hi.
+func Between(lo, hi float64) Range {
+	return Range{Min: &lo, Max: &hi}
+}
+
+func (r Range) String() string {
+	format := func(v *float64) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	}
+
+	if r.Min != nil && r.Max != nil && *r.Min == *r.Max {
+		return format(r.Min)
+	}
+
+	return format(r.Min) + ".." + format(r.Max)
+}
+
+// ParseRange parses a range such as `5`, `..10`, `5..` or `5..10`.
+func ParseRange(s string) (Range, error) {
+	parse := func(s string) (*float64, error) {
+		if s == "" {
+			return nil, nil
+		}
+
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		return &v, nil
+	}
+
+	lo, hi, isRange := strings.Cut(s, "..")
+	if !isRange {
+		v, err := parse(s)
+		if err != nil || v == nil {
+			return Range{}, fmt.Errorf("invalid range %q", s)
+		}
+		return Range{Min: v, Max: v}, nil
+	}
+
+	r := Range{}
+
+	var err error
+	if r.Min, err = parse(lo); err != nil {
+		return Range{}, fmt.Errorf("invalid range %q", s)
+	}
+
+	if r.Max, err = parse(hi); err != nil || r.Min == nil && r.Max == nil {
+		return Range{}, fmt.Errorf("invalid range %q", s)
+	}
+
+	return r, nil
+}
+
+func (r Range) validate(nonNegative bool) error {
+	if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+		return fmt.Errorf("min of %s exceeds max", r)
+	}
+
+	if nonNegative && (r.Min != nil && *r.Min < 0 || r.Max != nil && *r.Max < 0) {
+		return fmt.Errorf("%s must not be negative", r)
+	}
+
+	return nil
+}
+
+// Arg is an argument of a selector, such as `type=!minecraft:zombie`.
+type Arg struct {
+	Name   string
+	Negate bool
+	Value  string
+}
+
+// ScoreRange is a score predicate of a selector.
+type ScoreRange struct {
+	Objective string
+	Range     Range
+}
+
+// Selector is a target selector, such as `@e[type=minecraft:zombie,limit=5]`.
+// Build it from a variable and typed arguments, or parse it with
+// ParseSelector, and pass its String to commands.
+type Selector struct {
+	// Variable is one of p, a, r, s, e and n.
+	Variable byte
+	Args     []Arg
+	// Scores are rendered at the position of the `scores` argument.
+	Scores []ScoreRange
+}
+
+// NearestPlayer returns `@p`.
+func NearestPlayer() Selector { return Selector{Variable: 'p'} }
+
+// AllPlayers returns `@a`.
+func AllPlayers() Selector { return Selector{Variable: 'a'} }
+
+// RandomPlayer returns `@r`.
+func RandomPlayer() Selector { return Selector{Variable: 'r'} }
+
+// Self returns `@s`.
+func Self() Selector { return Selector{Variable: 's'} }
+
+// Entities returns `@e`.
+func Entities() Selector { return Selector{Variable: 'e'} }
+
+// NearestEntity returns `@n`.
+func NearestEntity() Selector { return Selector{Variable: 'n'} }
+
+func (s Selector) with(name string, negate bool, value string) Selector {
+	s.Args = append(append([]Arg(nil), s.Args...), Arg{Name: name, Negate: negate, Value: value})
+	return s
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// Type selects entities of a type.
+func (s Selector) Type(entity string) Selector { return s.with("type", false, resource(entity)) }
+
+// NotType excludes entities of a type.
+func (s Selector) NotType(entity string) Selector { return s.with("type", true, resource(entity)) }
+
+// Name selects entities by name.
+func (s Selector) Name(name string) Selector { return s.with("name", false, Quote(name)) }
+
+// NotName excludes entities by name.
+func (s Selector) NotName(name string) Selector { return s.with("name", true, Quote(name)) }
+
+// Tag selects entities with a tag. An empty tag selects entities without
+// tags.
+func (s Selector) Tag(tag string) Selector { return s.with("tag", false, tag) }
+
+// NotTag excludes entities with a tag. An empty tag excludes entities without
+// tags.
+func (s Selector) NotTag(tag string) Selector { return s.with("tag", true, tag) }
+
+// Team selects entities on a team. An empty team selects entities on no team.
+func (s Selector) Team(team string) Selector { return s.with("team", false, team) }
+
+// NotTeam excludes entities on a team.
+func (s Selector) NotTeam(team string) Selector { return s.with("team", true, team) }
+
+// GameMode selects players in a game mode.
+func (s Selector) GameMode(mode GameMode) Selector { return s.with("gamemode", false, string(mode)) }
+
+// NotGameMode excludes players in a game mode.
+func (s Selector) NotGameMode(mode GameMode) Selector { return s.with("gamemode", true, string(mode)) }
+
+// Distance selects entities within a distance.
+func (s Selector) Distance(r Range) Selector { return s.with("distance", false, r.String()) }
+
+// Level selects players by experience level.
+func (s Selector) Level(r Range) Selector { return s.with("level", false, r.String()) }
+
+// XRotation selects entities by pitch.
+func (s Selector) XRotation(r Range) Selector { return s.with("x_rotation", false, r.String()) }
+
+// YRotation selects entities by yaw.
+func (s Selector) YRotation(r Range) Selector { return s.with("y_rotation", false, r.String()) }
+
+// At moves the origin of the selector.
+func (s Selector) At(x, y, z float64) Selector {
+	return s.with("x", false, formatFloat(x)).with("y", false, formatFloat(y)).with("z", false, formatFloat(z))
+}
+
+// Volume selects entities within a box from the origin.
+func (s Selector) Volume(dx, dy, dz float64) Selector {
+	return s.with("dx", false, formatFloat(dx)).with("dy", false, formatFloat(dy)).with("dz", false, formatFloat(dz))
+}
+
+// Limit limits the number of entities.
+func (s Selector) Limit(n int) Selector { return s.with("limit", false, strconv.Itoa(n)) }
+
+// Sort sorts the entities.
+func (s Selector) Sort(sort Sort) Selector { return s.with("sort", false, string(sort)) }
+
+// NBT selects entities whose data matches snbt, as built by snbt.Marshal.
+func (s Selector) NBT(snbt string) Selector { return s.with("nbt", false, snbt) }
+
+// NotNBT excludes entities whose data matches snbt.
+func (s Selector) NotNBT(snbt string) Selector { return s.with("nbt", true, snbt) }
+
+// Predicate selects entities matching a predicate.
+func (s Selector) Predicate(id string) Selector { return s.with("predicate", false, resource(id)) }
+
+// Score selects entities whose score of objective is within r.
+func (s Selector) Score(objective string, r Range) Selector {
+	if !s.hasArg("scores") {
+		s = s.with("scores", false, "")
+	}
+	s.Scores = append(append([]ScoreRange(nil), s.Scores...), ScoreRange{Objective: objective, Range: r})
+
+	return s
+}
+
+func (s Selector) hasArg(name string) bool {
+	for _, a := range s.Args {
+		if a.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s Selector) String() string {
+	var b strings.Builder
+	b.WriteByte('@')
+	b.WriteByte(s.Variable)
+
+	if len(s.Args) == 0 {
+		return b.String()
+	}
+
+	b.WriteByte('[')
+	for i, a := range s.Args {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		b.WriteString(a.Name)
+		b.WriteByte('=')
+		if a.Negate {
+			b.WriteByte('!')
+		}
+
+		if a.Name == "scores" {
+			b.WriteByte('{')
+			for j, sc := range s.Scores {
+				if j > 0 {
+					b.WriteByte(',')
+				}
+				b.WriteString(sc.Objective + "=" + sc.Range.String())
+			}
+			b.WriteByte('}')
+			continue
+		}
+
+		b.WriteString(a.Value)
+	}
+	b.WriteByte(']')
+
+	return b.String()
+}
+
+// selectorArgs lists the known arguments, and whether each may be repeated
+// and negated.
+var selectorArgs = map[string]struct{ repeat, negate bool }{
+	"x":            {},
+	"y":            {},
+	"z":            {},
+	"dx":           {},
+	"dy":           {},
+	"dz":           {},
+	"distance":     {},
+	"level":        {},
+	"x_rotation":   {},
+	"y_rotation":   {},
+	"limit":        {},
+	"sort":         {},
+	"scores":       {},
+	"advancements": {},
+	"type":         {negate: true},
+	"name":         {negate: true},
+	"team":         {negate: true},
+	"gamemode":     {negate: true},
+	"tag":          {repeat: true, negate: true},
+	"nbt":          {repeat: true, negate: true},
+	"predicate":    {repeat: true, negate: true},
+}
+
+// Validate checks the variable and the names, values and ranges of the
+// arguments.
+func (s Selector) Validate() error {
+	fail := func(format string, args ...any) error {
+		return &SelectorError{Selector: s.String(), Msg: fmt.Sprintf(format, args...)}
+	}
+
+	if strings.IndexByte("parsen", s.Variable) < 0 {
+		return fail("unknown variable %q", s.Variable)
+	}
+
+	seen := map[string]bool{}
+	for _, a := range s.Args {
+		spec, ok := selectorArgs[a.Name]
+		if !ok {
+			return fail("unknown argument %q", a.Name)
+		}
+
+		if a.Negate && !spec.negate {
+			return fail("argument %q cannot be negated", a.Name)
+		}
+
+		// NOTE: negated arguments may be repeated, e.g. type=!a,type=!b
+		if !spec.repeat && !a.Negate {
+			if seen[a.Name] {
+				return fail("argument %q is repeated", a.Name)
+			}
+			seen[a.Name] = true
+		}
+
+		if err := validateArg(s.Variable, a); err != nil {
+			return fail("argument %q: %v", a.Name, err)
+		}
+	}
+
+	for _, sc := range s.Scores {
+		if sc.Objective == "" || strings.IndexFunc(sc.Objective, func(r rune) bool { return !isUnquotedRune(r) }) >= 0 {
+			return fail("invalid objective %q", sc.Objective)
+		}
+
+		if err := sc.Range.validate(false); err != nil {
+			return fail("score %q: %v", sc.Objective, err)
+		}
+	}
+
+	return nil
+}
+
+func validateArg(variable byte, a Arg) error {
+	switch a.Name {
+	case "x", "y", "z", "dx", "dy", "dz":
+		if _, err := strconv.ParseFloat(a.Value, 64); err != nil {
+			return fmt.Errorf("invalid number %q", a.Value)
+		}
+	case "distance", "level", "x_rotation", "y_rotation":
+		r, err := ParseRange(a.Value)
+		if err != nil {
+			return err
+		}
+		return r.validate(a.Name == "distance" || a.Name == "level")
+	case "limit":
+		if n, err := strconv.Atoi(a.Value); err != nil || n < 1 {
+			return fmt.Errorf("limit must be at least 1")
+		}
+		if variable == 's' {
+			return fmt.Errorf("not applicable to @s")
+		}
+	case "sort":
+		switch Sort(a.Value) {
+		case SortNearest, SortFurthest, SortRandom, SortArbitrary:
+		default:
+			return fmt.Errorf("unknown sort %q", a.Value)
+		}
+		if variable == 's' {
+			return fmt.Errorf("not applicable to @s")
+		}
+	case "gamemode":
+		switch GameMode(a.Value) {
+		case Survival, Creative, Adventure, Spectator:
+		default:
+			return fmt.Errorf("unknown game mode %q", a.Value)
+		}
+	case "type":
+		if variable == 'a' || variable == 'p' || variable == 'r' {
+			return fmt.Errorf("not applicable to @%c", variable)
+		}
+		if strings.TrimPrefix(a.Value, "#") == "" {
+			return fmt.Errorf("empty type")
+		}
+	case "nbt":
+		if !strings.HasPrefix(a.Value, "{") {
+			return fmt.Errorf("nbt must be a compound")
+		}
+		if _, err := snbt.Parse(a.Value); err != nil {
+			return err
+		}
+	case "name":
+		if a.Value == "" {
+			return fmt.Errorf("empty name")
+		}
+	case "tag", "team":
+		if strings.IndexFunc(a.Value, func(r rune) bool { return !isUnquotedRune(r) }) >= 0 {
+			return fmt.Errorf("invalid %s %q", a.Name, a.Value)
+		}
+	case "advancements":
+		if !strings.HasPrefix(a.Value, "{") || !strings.HasSuffix(a.Value, "}") {
+			return fmt.Errorf("advancements must be in braces")
+		}
+	}
+
+	return nil
+}
+
+// ParseSelector parses and validates a selector such as
+// `@e[type=minecraft:zombie,distance=..10]`.
+func ParseSelector(s string) (Selector, error) {
+	fail := func(msg string) (Selector, error) {
+		return Selector{}, &SelectorError{Selector: s, Msg: msg}
+	}
+
+	if len(s) < 2 || s[0] != '@' {
+		return fail("missing variable")
+	}
+
+	sel := Selector{Variable: s[1]}
+	rest := s[2:]
+
+	if rest != "" {
+		if rest[0] != '[' || rest[len(rest)-1] != ']' {
+			return fail("arguments must be in brackets")
+		}
+
+		body := rest[1 : len(rest)-1]
+		parts, err := splitTopLevel(body, ',')
+		if err != nil {
+			return fail(err.Error())
+		}
+
+		for _, part := range parts {
+			name, value, ok := strings.Cut(part, "=")
+			if !ok {
+				return fail(fmt.Sprintf("argument %q has no value", part))
+			}
+
+			a := Arg{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)}
+			if strings.HasPrefix(a.Value, "!") {
+				a.Negate = true
+				a.Value = strings.TrimSpace(a.Value[1:])
+			}
+
+			if a.Name == "scores" {
+				scores, err := parseScores(a.Value)
+				if err != nil {
+					return fail(err.Error())
+				}
+				sel.Scores = append(sel.Scores, scores...)
+			}
+
+			sel.Args = append(sel.Args, a)
+		}
+	}
+
+	if err := sel.Validate(); err != nil {
+		return Selector{}, err
+	}
+
+	return sel, nil
+}
+
+func parseScores(s string) ([]ScoreRange, error) {
+	if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+		return nil, fmt.Errorf("scores must be in braces")
+	}
+
+	body := strings.TrimSpace(s[1 : len(s)-1])
+	if body == "" {
+		return []ScoreRange{}, nil
+	}
+
+	scores := []ScoreRange{}
+	for _, part := range strings.Split(body, ",") {
+		objective, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("score %q has no range", part)
+		}
+
+		r, err := ParseRange(strings.TrimSpace(value))
+		if err != nil {
+			return nil, err
+		}
+
+		scores = append(scores, ScoreRange{Objective: strings.TrimSpace(objective), Range: r})
+	}
+
+	return scores, nil
+}
+
+// splitTopLevel splits s at sep outside of quotes, braces and brackets.
+func splitTopLevel(s string, sep byte) ([]string, error) {
+	parts := []string{}
+
+	depth := 0
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced %q", c)
+			}
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated string")
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced brackets")
+	}
+
+	if strings.TrimSpace(s) != "" {
+		parts = append(parts, s[start:])
+	}
+
+	return parts, nil
+}
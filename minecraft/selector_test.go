@@ -0,0 +1,313 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package minecraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRange(t *testing.T) {
+	cases := []struct {
+		name     string
+		s        string
+		expected Range
+	}{
+		{
+			name:     "positive case: exact",
+			s:        "5",
+			expected: Exactly(5),
+		},
+		{
+			name:     "positive case: at most",
+			s:        "..10",
+			expected: AtMost(10),
+		},
+		{
+			name:     "positive case: at least",
+			s:        "0.5..",
+			expected: AtLeast(0.5),
+		},
+		{
+			name:     "positive case: between",
+			s:        "-1..1",
+			expected: Between(-1, 1),
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseRange(tt.s)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.s, actual.String())
+		})
+	}
+
+	for _, s := range []string{"", "..", "a", "1..b"} {
+		_, err := ParseRange(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestSelector_String(t *testing.T) {
+	cases := []struct {
+		name     string
+		selector Selector
+		expected string
+	}{
+		{
+			name:     "positive case: variable",
+			selector: AllPlayers(),
+			expected: "@a",
+		},
+		{
+			name:     "positive case: arguments",
+			selector: Entities().Type("zombie").Distance(AtMost(10)).Limit(5).Sort(SortNearest),
+			expected: "@e[type=minecraft:zombie,distance=..10,limit=5,sort=nearest]",
+		},
+		{
+			name:     "positive case: negation",
+			selector: Entities().NotType("player").NotType("item").NotTag("boss").NotGameMode(Spectator),
+			expected: "@e[type=!minecraft:player,type=!minecraft:item,tag=!boss,gamemode=!spectator]",
+		},
+		{
+			name:     "positive case: quoted name",
+			selector: Entities().Name(`Big "Boss"`),
+			expected: `@e[name="Big \"Boss\""]`,
+		},
+		{
+			name:     "positive case: position",
+			selector: Entities().At(0, 64, -10.5).Volume(10, 5, 10),
+			expected: "@e[x=0,y=64,z=-10.5,dx=10,dy=5,dz=10]",
+		},
+		{
+			name:     "positive case: scores",
+			selector: AllPlayers().Score("kills", AtLeast(1)).Team("red").Score("deaths", Exactly(0)),
+			expected: "@a[scores={kills=1..,deaths=0},team=red]",
+		},
+		{
+			name:     "positive case: nbt",
+			selector: NearestPlayer().NBT(`{SelectedItem:{id:"minecraft:diamond"}}`).Level(AtLeast(30)),
+			expected: `@p[nbt={SelectedItem:{id:"minecraft:diamond"}},level=30..]`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.selector.String())
+			assert.NoError(t, tt.selector.Validate())
+		})
+	}
+}
+
+func TestSelector_immutable(t *testing.T) {
+	base := Entities().Type("zombie")
+	a := base.Limit(1)
+	b := base.Limit(2)
+
+	assert.Equal(t, "@e[type=minecraft:zombie]", base.String())
+	assert.Equal(t, "@e[type=minecraft:zombie,limit=1]", a.String())
+	assert.Equal(t, "@e[type=minecraft:zombie,limit=2]", b.String())
+}
+
+func TestSelector_Validate(t *testing.T) {
+	cases := []struct {
+		name     string
+		selector Selector
+	}{
+		{
+			name:     "negative case: unknown variable",
+			selector: Selector{Variable: 'x'},
+		},
+		{
+			name:     "negative case: unknown argument",
+			selector: Entities().with("foo", false, "1"),
+		},
+		{
+			name:     "negative case: repeated argument",
+			selector: Entities().Limit(1).Limit(2),
+		},
+		{
+			name:     "negative case: repeated type",
+			selector: Entities().Type("zombie").Type("skeleton"),
+		},
+		{
+			name:     "negative case: negated limit",
+			selector: Entities().with("limit", true, "1"),
+		},
+		{
+			name:     "negative case: zero limit",
+			selector: Entities().Limit(0),
+		},
+		{
+			name:     "negative case: limit on self",
+			selector: Self().Limit(1),
+		},
+		{
+			name:     "negative case: negative distance",
+			selector: Entities().Distance(AtLeast(-1)),
+		},
+		{
+			name:     "negative case: inverted range",
+			selector: Entities().XRotation(Between(10, -10)),
+		},
+		{
+			name:     "negative case: type on players",
+			selector: AllPlayers().Type("zombie"),
+		},
+		{
+			name:     "negative case: type on random player",
+			selector: RandomPlayer().Type("zombie"),
+		},
+		{
+			name:     "negative case: unknown sort",
+			selector: Entities().Sort("closest"),
+		},
+		{
+			name:     "negative case: unknown game mode",
+			selector: AllPlayers().GameMode("hardcore"),
+		},
+		{
+			name:     "negative case: invalid nbt",
+			selector: Entities().NBT("{a:"),
+		},
+		{
+			name:     "negative case: nbt not compound",
+			selector: Entities().NBT("1b"),
+		},
+		{
+			name:     "negative case: invalid tag",
+			selector: Entities().Tag("a b"),
+		},
+		{
+			name:     "negative case: invalid objective",
+			selector: Entities().Score("a b", AtLeast(1)),
+		},
+		{
+			name:     "negative case: inverted score",
+			selector: Entities().Score("kills", Between(5, 1)),
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.selector.Validate()
+			assert.IsType(t, &SelectorError{}, err)
+		})
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	cases := []struct {
+		name     string
+		s        string
+		expected Selector
+		err      error
+	}{
+		{
+			name:     "positive case: variable",
+			s:        "@s",
+			expected: Self(),
+		},
+		{
+			name:     "positive case: arguments",
+			s:        "@e[type=minecraft:zombie,distance=..10,limit=5,sort=nearest]",
+			expected: Entities().Type("zombie").Distance(AtMost(10)).Limit(5).Sort(SortNearest),
+		},
+		{
+			name:     "positive case: negation and spaces",
+			s:        "@e[type = !minecraft:player, tag=!boss]",
+			expected: Entities().NotType("player").NotTag("boss"),
+		},
+		{
+			name:     "positive case: quoted name with comma",
+			s:        `@e[name="a, b"]`,
+			expected: Entities().Name("a, b"),
+		},
+		{
+			name:     "positive case: nbt with commas",
+			s:        `@e[nbt={Tags:["a","b"],NoAI:1b},limit=1]`,
+			expected: Entities().NBT(`{Tags:["a","b"],NoAI:1b}`).Limit(1),
+		},
+		{
+			name:     "positive case: scores",
+			s:        "@a[scores={kills=1..,deaths=0}]",
+			expected: AllPlayers().Score("kills", AtLeast(1)).Score("deaths", Exactly(0)),
+		},
+		{
+			name: "negative case: missing variable",
+			s:    "jeb_",
+			err:  &SelectorError{},
+		},
+		{
+			name: "negative case: unbalanced",
+			s:    "@e[nbt={a:1]",
+			err:  &SelectorError{},
+		},
+		{
+			name: "negative case: no value",
+			s:    "@e[limit]",
+			err:  &SelectorError{},
+		},
+		{
+			name: "negative case: invalid argument",
+			s:    "@e[limit=0]",
+			err:  &SelectorError{},
+		},
+		{
+			name: "negative case: type on random player",
+			s:    "@r[type=zombie]",
+			err:  &SelectorError{},
+		},
+		{
+			name: "negative case: invalid scores",
+			s:    "@a[scores={kills}]",
+			err:  &SelectorError{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseSelector(tt.s)
+
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.String(), actual.String())
+			} else {
+				assert.IsType(t, tt.err, err)
+			}
+		})
+	}
+}
+
+func TestSelector_command(t *testing.T) {
+	assert.Equal(t, Cmd("/kill @e[type=minecraft:zombie,limit=5]"), Kill(Entities().Type("zombie").Limit(5).String()))
+}
+
+func TestSelectorError_Error(t *testing.T) {
+	_, err := ParseSelector("@e[limit=0]")
+	assert.EqualError(t, err, `minecraft selector "@e[limit=0]": argument "limit": limit must be at least 1`)
+}
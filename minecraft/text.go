@@ -0,0 +1,217 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package minecraft
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Color is the color of a Text, either a named color or a hex color.
+type Color string
+
+const (
+	Black       Color = "black"
+	DarkBlue    Color = "dark_blue"
+	DarkGreen   Color = "dark_green"
+	DarkAqua    Color = "dark_aqua"
+	DarkRed     Color = "dark_red"
+	DarkPurple  Color = "dark_purple"
+	Gold        Color = "gold"
+	Gray        Color = "gray"
+	DarkGray    Color = "dark_gray"
+	Blue        Color = "blue"
+	Green       Color = "green"
+	Aqua        Color = "aqua"
+	Red         Color = "red"
+	LightPurple Color = "light_purple"
+	Yellow      Color = "yellow"
+	White       Color = "white"
+)
+
+// Hex returns the color of a 24-bit RGB value, such as 0xFF5555.
+func Hex(rgb uint32) Color {
+	return Color(fmt.Sprintf("#%06X", rgb&0xFFFFFF))
+}
+
+// ClickEvent is the action of clicking a Text in chat.
+type ClickEvent struct {
+	Action string `json:"action"`
+	Value  string `json:"value"`
+}
+
+// OpenURL opens url.
+func OpenURL(url string) *ClickEvent {
+	return &ClickEvent{Action: "open_url", Value: url}
+}
+
+// RunCommand runs command as the player.
+func RunCommand(command string) *ClickEvent {
+	return &ClickEvent{Action: "run_command", Value: command}
+}
+
+// SuggestCommand puts command in the chat box.
+func SuggestCommand(command string) *ClickEvent {
+	return &ClickEvent{Action: "suggest_command", Value: command}
+}
+
+// CopyToClipboard copies s to the clipboard.
+func CopyToClipboard(s string) *ClickEvent {
+	return &ClickEvent{Action: "copy_to_clipboard", Value: s}
+}
+
+// ChangePage turns a book to page.
+func ChangePage(page int) *ClickEvent {
+	return &ClickEvent{Action: "change_page", Value: strconv.Itoa(page)}
+}
+
+// HoverEvent is the tooltip shown when hovering a Text in chat.
+type HoverEvent struct {
+	Action   string `json:"action"`
+	Contents any    `json:"contents"`
+}
+
+// ShowText shows a text.
+func ShowText(t Text) *HoverEvent {
+	return &HoverEvent{Action: "show_text", Contents: t}
+}
+
+// ShowItem shows the tooltip of count items.
+func ShowItem(item string, count int) *HoverEvent {
+	return &HoverEvent{Action: "show_item", Contents: map[string]any{"id": resource(item), "count": count}}
+}
+
+// ShowEntity shows an entity by type and UUID, with an optional name.
+func ShowEntity(entity, uuid string, name *Text) *HoverEvent {
+	contents := map[string]any{"type": resource(entity), "id": uuid}
+	if name != nil {
+		contents["name"] = *name
+	}
+
+	return &HoverEvent{Action: "show_entity", Contents: contents}
+}
+
+// Text is a JSON text component, as taken by tellraw, title and bossbar.
+// Unset styles are inherited from the parent component.
+type Text struct {
+	Text      string `json:"text,omitempty"`
+	Translate string `json:"translate,omitempty"`
+	With      []Text `json:"with,omitempty"`
+
+	Color         Color  `json:"color,omitempty"`
+	Font          string `json:"font,omitempty"`
+	Bold          *bool  `json:"bold,omitempty"`
+	Italic        *bool  `json:"italic,omitempty"`
+	Underlined    *bool  `json:"underlined,omitempty"`
+	Strikethrough *bool  `json:"strikethrough,omitempty"`
+	Obfuscated    *bool  `json:"obfuscated,omitempty"`
+	Insertion     string `json:"insertion,omitempty"`
+
+	ClickEvent *ClickEvent `json:"clickEvent,omitempty"`
+	HoverEvent *HoverEvent `json:"hoverEvent,omitempty"`
+
+	Extra []Text `json:"extra,omitempty"`
+}
+
+// NewText returns a plain text component.
+func NewText(s string) Text {
+	return Text{Text: s}
+}
+
+// NewTranslation returns a component translated on the client, such as
+// `chat.type.announcement`, filled in with the arguments.
+func NewTranslation(key string, with ...Text) Text {
+	return Text{Translate: key, With: with}
+}
+
+// WithColor returns t with the color c.
+func (t Text) WithColor(c Color) Text {
+	t.Color = c
+	return t
+}
+
+// WithBold returns t in bold, or explicitly not in bold.
+func (t Text) WithBold(b bool) Text {
+	t.Bold = &b
+	return t
+}
+
+// WithItalic returns t in italics, or explicitly not in italics.
+func (t Text) WithItalic(b bool) Text {
+	t.Italic = &b
+	return t
+}
+
+// WithUnderlined returns t underlined, or explicitly not underlined.
+func (t Text) WithUnderlined(b bool) Text {
+	t.Underlined = &b
+	return t
+}
+
+// WithStrikethrough returns t struck through, or explicitly not struck
+// through.
+func (t Text) WithStrikethrough(b bool) Text {
+	t.Strikethrough = &b
+	return t
+}
+
+// WithObfuscated returns t obfuscated, or explicitly not obfuscated.
+func (t Text) WithObfuscated(b bool) Text {
+	t.Obfuscated = &b
+	return t
+}
+
+// OnClick returns t with the click event e.
+func (t Text) OnClick(e *ClickEvent) Text {
+	t.ClickEvent = e
+	return t
+}
+
+// OnHover returns t with the hover event e.
+func (t Text) OnHover(e *HoverEvent) Text {
+	t.HoverEvent = e
+	return t
+}
+
+// Append returns t followed by the children, which inherit its style.
+func (t Text) Append(children ...Text) Text {
+	t.Extra = append(append([]Text(nil), t.Extra...), children...)
+	return t
+}
+
+func (t Text) MarshalJSON() ([]byte, error) {
+	type text Text
+
+	if t.Translate != "" {
+		return json.Marshal(text(t))
+	}
+
+	// NOTE: a component without content must still have the text key
+	return json.Marshal(struct {
+		Text string `json:"text"`
+		text
+	}{Text: t.Text, text: text(t)})
+}
+
+func (t Text) String() string {
+	return component(t)
+}
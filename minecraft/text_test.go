@@ -0,0 +1,110 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package minecraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestText_MarshalJSON(t *testing.T) {
+	cases := []struct {
+		name     string
+		text     Text
+		expected string
+	}{
+		{
+			name:     "positive case: plain",
+			text:     NewText("hi"),
+			expected: `{"text":"hi"}`,
+		},
+		{
+			name:     "positive case: empty",
+			text:     Text{},
+			expected: `{"text":""}`,
+		},
+		{
+			name:     "positive case: style",
+			text:     NewText("hi").WithColor(Red).WithBold(true).WithItalic(false).WithUnderlined(true).WithStrikethrough(true).WithObfuscated(true),
+			expected: `{"text":"hi","color":"red","bold":true,"italic":false,"underlined":true,"strikethrough":true,"obfuscated":true}`,
+		},
+		{
+			name:     "positive case: hex color",
+			text:     NewText("hi").WithColor(Hex(0xff5555)),
+			expected: `{"text":"hi","color":"#FF5555"}`,
+		},
+		{
+			name:     "positive case: translation",
+			text:     NewTranslation("chat.type.announcement", NewText("Server"), NewText("hi")),
+			expected: `{"translate":"chat.type.announcement","with":[{"text":"Server"},{"text":"hi"}]}`,
+		},
+		{
+			name:     "positive case: click",
+			text:     NewText("[vote]").OnClick(RunCommand("/trigger vote")),
+			expected: `{"text":"[vote]","clickEvent":{"action":"run_command","value":"/trigger vote"}}`,
+		},
+		{
+			name:     "positive case: hover text",
+			text:     NewText("?").OnHover(ShowText(NewText("help").WithColor(Gray))),
+			expected: `{"text":"?","hoverEvent":{"action":"show_text","contents":{"text":"help","color":"gray"}}}`,
+		},
+		{
+			name:     "positive case: hover item",
+			text:     NewText("item").OnHover(ShowItem("diamond", 2)),
+			expected: `{"text":"item","hoverEvent":{"action":"show_item","contents":{"count":2,"id":"minecraft:diamond"}}}`,
+		},
+		{
+			name:     "positive case: hover entity",
+			text:     NewText("jeb_").OnHover(ShowEntity("player", "853c80ef-3c37-49fd-aa49-938b674adae6", nil)),
+			expected: `{"text":"jeb_","hoverEvent":{"action":"show_entity","contents":{"id":"853c80ef-3c37-49fd-aa49-938b674adae6","type":"minecraft:player"}}}`,
+		},
+		{
+			name:     "positive case: extra",
+			text:     Text{}.Append(NewText("a").WithColor(Gold), NewText("b").OnClick(OpenURL("https://example.com"))),
+			expected: `{"text":"","extra":[{"text":"a","color":"gold"},{"text":"b","clickEvent":{"action":"open_url","value":"https://example.com"}}]}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.text.String())
+		})
+	}
+}
+
+func TestText_Append(t *testing.T) {
+	base := NewText("a").Append(NewText("b"))
+	c1 := base.Append(NewText("c"))
+	c2 := base.Append(NewText("d"))
+
+	assert.Len(t, base.Extra, 1)
+	assert.Equal(t, "c", c1.Extra[1].Text)
+	assert.Equal(t, "d", c2.Extra[1].Text)
+}
+
+func TestClickEvents(t *testing.T) {
+	assert.Equal(t, &ClickEvent{Action: "suggest_command", Value: "/msg "}, SuggestCommand("/msg "))
+	assert.Equal(t, &ClickEvent{Action: "copy_to_clipboard", Value: "x"}, CopyToClipboard("x"))
+	assert.Equal(t, &ClickEvent{Action: "change_page", Value: "2"}, ChangePage(2))
+}
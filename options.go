@@ -0,0 +1,104 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package rcon
+
+import (
+	liblog "github.com/Aton-Kish/gorcon/log"
+)
+
+// Option configures a connection created by Dial, DialTimeout or DialContext.
+type Option func(*options)
+
+type options struct {
+	logger      liblog.LeveledLogger
+	redactor    Redactor
+	hooks       Hooks
+	middlewares []Middleware
+
+	maxCommandSize int
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{hooks: NopHooks{}, maxCommandSize: DefaultMaxCommandSize}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithLogger sets the logger of the connection, overriding the package-wide
+// logger set by SetLogger. Records at LevelInfo and above are written.
+func WithLogger(l liblog.Logger) Option {
+	return func(o *options) {
+		o.logger = liblog.NewStdLogger(l, liblog.LevelInfo)
+	}
+}
+
+// WithLeveledLogger is like WithLogger, but takes a structured logger.
+func WithLeveledLogger(l liblog.LeveledLogger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithRedactor sets the Redactor applied to commands before they are logged.
+// Auth payloads are always redacted.
+func WithRedactor(r Redactor) Option {
+	return func(o *options) {
+		o.redactor = r
+	}
+}
+
+// WithHooks sets the Hooks notified of client activity, such as the Collector
+// of the metrics package.
+func WithHooks(h Hooks) Option {
+	return func(o *options) {
+		if h == nil {
+			h = NopHooks{}
+		}
+
+		o.hooks = h
+	}
+}
+
+// WithMiddleware appends middlewares around every command of the connection.
+// The first middleware is the outermost.
+func WithMiddleware(mws ...Middleware) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, mws...)
+	}
+}
+
+// WithLimiter rate limits the commands of the connection with l. It is
+// installed as a middleware after those already added.
+func WithLimiter(l *Limiter) Option {
+	return WithMiddleware(l.Middleware())
+}
+
+// WithMaxCommandSize sets the largest command, in bytes, the connection sends.
+// Longer commands fail with ErrPayloadTooLarge. A size of zero or less
+// disables the check, e.g. for modded servers.
+func WithMaxCommandSize(n int) Option {
+	return func(o *options) {
+		o.maxCommandSize = n
+	}
+}
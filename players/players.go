@@ -0,0 +1,303 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package players manages players through RCON: the online list, the
+// whitelist, operators, bans and kicks.
+//
+//	s := players.New(conn)
+//	res, err := s.WhitelistAdd(ctx, "jeb_")
+//	if err == nil && res.Outcome == players.Unchanged {
+//		// already whitelisted
+//	}
+package players
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+)
+
+// Outcome tells the results of an operation apart.
+type Outcome int
+
+const (
+	// Applied means the operation changed something.
+	Applied Outcome = iota
+	// Unchanged means nothing changed, e.g. the player is already
+	// whitelisted.
+	Unchanged
+	// NotFound means the player does not exist or is not online.
+	NotFound
+	// Invalid means the server rejected the target, e.g. an IP address it
+	// does not support. Malformed targets are never sent; see
+	// ErrInvalidTarget.
+	Invalid
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Applied:
+		return "applied"
+	case Unchanged:
+		return "unchanged"
+	case NotFound:
+		return "not found"
+	case Invalid:
+		return "invalid"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrInvalidTarget is returned, without sending anything, when a target is
+// not a player name, a UUID or an IP address as expected. Target selectors
+// such as "@a" are rejected, since they may match every player.
+var ErrInvalidTarget = errors.New("invalid target")
+
+var (
+	playerName = regexp.MustCompile(`^[A-Za-z0-9_]{1,16}$`)
+	playerUUID = regexp.MustCompile(`^[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}$`)
+)
+
+func checkPlayer(player string) error {
+	if playerName.MatchString(player) || playerUUID.MatchString(player) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q is not a player name or UUID", ErrInvalidTarget, player)
+}
+
+func checkIP(ip string) error {
+	if net.ParseIP(ip) != nil {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q is not an IP address", ErrInvalidTarget, ip)
+}
+
+// Result is the result of an operation.
+type Result struct {
+	Outcome Outcome
+	// Response is the raw response of the server.
+	Response string
+}
+
+// responses maps the prefixes of vanilla responses to outcomes.
+type responses map[string]Outcome
+
+var notFound = responses{
+	"That player does not exist": NotFound,
+	"No player was found":        NotFound,
+}
+
+// Service runs player management commands through a connection.
+type Service struct {
+	c minecraft.Commander
+}
+
+// New returns a Service using c, such as an rcon.RCON or an rcon.Client.
+func New(c minecraft.Commander) *Service {
+	return &Service{c: c}
+}
+
+// Online lists the online players with their UUIDs.
+func (s *Service) Online(ctx context.Context) (*minecraft.PlayerList, error) {
+	res, err := minecraft.Run(ctx, s.c, minecraft.Cmd("/list uuids"))
+	if err != nil {
+		return nil, err
+	}
+
+	return minecraft.ParseList(res)
+}
+
+// Whitelist lists the whitelisted players.
+func (s *Service) Whitelist(ctx context.Context) ([]string, error) {
+	res, err := minecraft.Run(ctx, s.c, minecraft.Cmd("/whitelist list"))
+	if err != nil {
+		return nil, err
+	}
+
+	return minecraft.ParseWhitelist(res)
+}
+
+// WhitelistAdd adds a player to the whitelist.
+func (s *Service) WhitelistAdd(ctx context.Context, player string) (*Result, error) {
+	if err := checkPlayer(player); err != nil {
+		return nil, err
+	}
+
+	return s.run(ctx, "whitelist add", "/whitelist add "+player, responses{
+		"Added ":                        Applied,
+		"Player is already whitelisted": Unchanged,
+	})
+}
+
+// WhitelistRemove removes a player from the whitelist.
+func (s *Service) WhitelistRemove(ctx context.Context, player string) (*Result, error) {
+	if err := checkPlayer(player); err != nil {
+		return nil, err
+	}
+
+	return s.run(ctx, "whitelist remove", "/whitelist remove "+player, responses{
+		"Removed ":                  Applied,
+		"Player is not whitelisted": Unchanged,
+	})
+}
+
+// WhitelistReload reloads the whitelist from disk.
+func (s *Service) WhitelistReload(ctx context.Context) (*Result, error) {
+	return s.run(ctx, "whitelist reload", "/whitelist reload", responses{
+		"Reloaded the whitelist": Applied,
+	})
+}
+
+// Op makes a player a server operator.
+func (s *Service) Op(ctx context.Context, player string) (*Result, error) {
+	if err := checkPlayer(player); err != nil {
+		return nil, err
+	}
+
+	return s.run(ctx, "op", "/op "+player, responses{
+		"Made ": Applied,
+		"Nothing changed. The player already is an operator": Unchanged,
+	})
+}
+
+// Deop makes a player no longer a server operator.
+func (s *Service) Deop(ctx context.Context, player string) (*Result, error) {
+	if err := checkPlayer(player); err != nil {
+		return nil, err
+	}
+
+	return s.run(ctx, "deop", "/deop "+player, responses{
+		"Made ": Applied,
+		"Nothing changed. The player is not an operator": Unchanged,
+	})
+}
+
+// Bans lists the banned players and IP addresses.
+func (s *Service) Bans(ctx context.Context) ([]minecraft.Ban, error) {
+	res, err := minecraft.Run(ctx, s.c, minecraft.Cmd("/banlist"))
+	if err != nil {
+		return nil, err
+	}
+
+	return minecraft.ParseBanList(res)
+}
+
+// Ban bans a player. An empty reason uses the server default.
+func (s *Service) Ban(ctx context.Context, player, reason string) (*Result, error) {
+	if err := checkPlayer(player); err != nil {
+		return nil, err
+	}
+
+	return s.run(ctx, "ban", withReason("/ban "+player, reason), responses{
+		"Banned ": Applied,
+		"Nothing changed. The player is already banned": Unchanged,
+	})
+}
+
+// BanIP bans an IP address, or the address of an online player.
+func (s *Service) BanIP(ctx context.Context, target, reason string) (*Result, error) {
+	// NOTE: the target may be the name of an online player
+	if checkIP(target) != nil && checkPlayer(target) != nil {
+		return nil, fmt.Errorf("%w: %q is not an IP address or a player", ErrInvalidTarget, target)
+	}
+
+	return s.run(ctx, "ban-ip", withReason("/ban-ip "+target, reason), responses{
+		"Banned IP ": Applied,
+		"Nothing changed. That IP is already banned": Unchanged,
+		"Invalid IP address or unknown player":       Invalid,
+	})
+}
+
+// Pardon unbans a player.
+func (s *Service) Pardon(ctx context.Context, player string) (*Result, error) {
+	if err := checkPlayer(player); err != nil {
+		return nil, err
+	}
+
+	return s.run(ctx, "pardon", "/pardon "+player, responses{
+		"Unbanned ": Applied,
+		"Nothing changed. The player isn't banned": Unchanged,
+	})
+}
+
+// PardonIP unbans an IP address.
+func (s *Service) PardonIP(ctx context.Context, ip string) (*Result, error) {
+	if err := checkIP(ip); err != nil {
+		return nil, err
+	}
+
+	return s.run(ctx, "pardon-ip", "/pardon-ip "+ip, responses{
+		"Unbanned IP ":                          Applied,
+		"Nothing changed. That IP isn't banned": Unchanged,
+		"Invalid IP address":                    Invalid,
+	})
+}
+
+// Kick kicks a player. An empty message uses the server default.
+func (s *Service) Kick(ctx context.Context, player, message string) (*Result, error) {
+	if err := checkPlayer(player); err != nil {
+		return nil, err
+	}
+
+	return s.run(ctx, "kick", withReason("/kick "+player, message), responses{
+		"Kicked ": Applied,
+	})
+}
+
+// run runs the command and matches the response against known prefixes.
+func (s *Service) run(ctx context.Context, name, command string, known responses) (*Result, error) {
+	res, err := minecraft.Run(ctx, s.c, minecraft.Cmd(command))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range []responses{known, notFound} {
+		// NOTE: the longest prefix wins, e.g. "Banned IP " over "Banned "
+		match, outcome := "", Outcome(0)
+		for prefix, o := range m {
+			if strings.HasPrefix(res, prefix) && len(prefix) > len(match) {
+				match, outcome = prefix, o
+			}
+		}
+
+		if match != "" {
+			return &Result{Outcome: outcome, Response: res}, nil
+		}
+	}
+
+	return nil, &minecraft.UnexpectedOutputError{Command: name, Output: res}
+}
+
+func withReason(command, reason string) string {
+	reason = strings.Join(strings.Fields(reason), " ")
+	if reason == "" {
+		return command
+	}
+
+	return command + " " + reason
+}
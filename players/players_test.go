@@ -0,0 +1,368 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package players
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCommander answers commands from responses.
+type mockCommander struct {
+	commands  []string
+	responses map[string]string
+}
+
+func (m *mockCommander) Command(command string) (string, error) {
+	return m.CommandContext(context.Background(), command)
+}
+
+func (m *mockCommander) CommandContext(ctx context.Context, command string) (string, error) {
+	m.commands = append(m.commands, command)
+
+	res, ok := m.responses[command]
+	if !ok {
+		return "", errors.New("unknown command")
+	}
+
+	return res, nil
+}
+
+func TestService_operations(t *testing.T) {
+	cases := []struct {
+		name     string
+		run      func(s *Service) (*Result, error)
+		command  string
+		response string
+		expected Outcome
+		err      error
+	}{
+		{
+			name:     "positive case: whitelist add",
+			run:      func(s *Service) (*Result, error) { return s.WhitelistAdd(context.Background(), "jeb_") },
+			command:  "/whitelist add jeb_",
+			response: "Added jeb_ to the whitelist",
+			expected: Applied,
+		},
+		{
+			name:     "positive case: whitelist add already whitelisted",
+			run:      func(s *Service) (*Result, error) { return s.WhitelistAdd(context.Background(), "jeb_") },
+			command:  "/whitelist add jeb_",
+			response: "Player is already whitelisted",
+			expected: Unchanged,
+		},
+		{
+			name:     "positive case: whitelist add unknown player",
+			run:      func(s *Service) (*Result, error) { return s.WhitelistAdd(context.Background(), "nobody_") },
+			command:  "/whitelist add nobody_",
+			response: "That player does not exist",
+			expected: NotFound,
+		},
+		{
+			name:     "positive case: whitelist remove",
+			run:      func(s *Service) (*Result, error) { return s.WhitelistRemove(context.Background(), "jeb_") },
+			command:  "/whitelist remove jeb_",
+			response: "Removed jeb_ from the whitelist",
+			expected: Applied,
+		},
+		{
+			name:     "positive case: whitelist remove not whitelisted",
+			run:      func(s *Service) (*Result, error) { return s.WhitelistRemove(context.Background(), "jeb_") },
+			command:  "/whitelist remove jeb_",
+			response: "Player is not whitelisted",
+			expected: Unchanged,
+		},
+		{
+			name:     "positive case: whitelist reload",
+			run:      func(s *Service) (*Result, error) { return s.WhitelistReload(context.Background()) },
+			command:  "/whitelist reload",
+			response: "Reloaded the whitelist",
+			expected: Applied,
+		},
+		{
+			name:     "positive case: op",
+			run:      func(s *Service) (*Result, error) { return s.Op(context.Background(), "jeb_") },
+			command:  "/op jeb_",
+			response: "Made jeb_ a server operator",
+			expected: Applied,
+		},
+		{
+			name:     "positive case: op already operator",
+			run:      func(s *Service) (*Result, error) { return s.Op(context.Background(), "jeb_") },
+			command:  "/op jeb_",
+			response: "Nothing changed. The player already is an operator",
+			expected: Unchanged,
+		},
+		{
+			name:     "positive case: deop",
+			run:      func(s *Service) (*Result, error) { return s.Deop(context.Background(), "jeb_") },
+			command:  "/deop jeb_",
+			response: "Made jeb_ no longer a server operator",
+			expected: Applied,
+		},
+		{
+			name:     "positive case: deop not operator",
+			run:      func(s *Service) (*Result, error) { return s.Deop(context.Background(), "jeb_") },
+			command:  "/deop jeb_",
+			response: "Nothing changed. The player is not an operator",
+			expected: Unchanged,
+		},
+		{
+			name:     "positive case: ban",
+			run:      func(s *Service) (*Result, error) { return s.Ban(context.Background(), "jeb_", "griefing\nspawn") },
+			command:  "/ban jeb_ griefing spawn",
+			response: "Banned jeb_: griefing spawn",
+			expected: Applied,
+		},
+		{
+			name:     "positive case: ban already banned",
+			run:      func(s *Service) (*Result, error) { return s.Ban(context.Background(), "jeb_", "") },
+			command:  "/ban jeb_",
+			response: "Nothing changed. The player is already banned",
+			expected: Unchanged,
+		},
+		{
+			name:     "positive case: ban unknown player",
+			run:      func(s *Service) (*Result, error) { return s.Ban(context.Background(), "nobody_", "") },
+			command:  "/ban nobody_",
+			response: "That player does not exist",
+			expected: NotFound,
+		},
+		{
+			name:     "positive case: ban ip",
+			run:      func(s *Service) (*Result, error) { return s.BanIP(context.Background(), "192.168.0.1", "spam") },
+			command:  "/ban-ip 192.168.0.1 spam",
+			response: "Banned IP 192.168.0.1: spam",
+			expected: Applied,
+		},
+		{
+			name:     "positive case: ban ip already banned",
+			run:      func(s *Service) (*Result, error) { return s.BanIP(context.Background(), "192.168.0.1", "") },
+			command:  "/ban-ip 192.168.0.1",
+			response: "Nothing changed. That IP is already banned",
+			expected: Unchanged,
+		},
+		{
+			name:     "positive case: ban ip invalid",
+			run:      func(s *Service) (*Result, error) { return s.BanIP(context.Background(), "nobody_", "") },
+			command:  "/ban-ip nobody_",
+			response: "Invalid IP address or unknown player",
+			expected: Invalid,
+		},
+		{
+			name:     "positive case: pardon",
+			run:      func(s *Service) (*Result, error) { return s.Pardon(context.Background(), "jeb_") },
+			command:  "/pardon jeb_",
+			response: "Unbanned jeb_",
+			expected: Applied,
+		},
+		{
+			name:     "positive case: pardon not banned",
+			run:      func(s *Service) (*Result, error) { return s.Pardon(context.Background(), "jeb_") },
+			command:  "/pardon jeb_",
+			response: "Nothing changed. The player isn't banned",
+			expected: Unchanged,
+		},
+		{
+			name:     "positive case: pardon ip",
+			run:      func(s *Service) (*Result, error) { return s.PardonIP(context.Background(), "192.168.0.1") },
+			command:  "/pardon-ip 192.168.0.1",
+			response: "Unbanned IP 192.168.0.1",
+			expected: Applied,
+		},
+		{
+			name:     "positive case: pardon ip not banned",
+			run:      func(s *Service) (*Result, error) { return s.PardonIP(context.Background(), "192.168.0.1") },
+			command:  "/pardon-ip 192.168.0.1",
+			response: "Nothing changed. That IP isn't banned",
+			expected: Unchanged,
+		},
+		{
+			name:     "positive case: pardon ip invalid",
+			run:      func(s *Service) (*Result, error) { return s.PardonIP(context.Background(), "::1") },
+			command:  "/pardon-ip ::1",
+			response: "Invalid IP address",
+			expected: Invalid,
+		},
+		{
+			name: "positive case: kick by uuid",
+			run: func(s *Service) (*Result, error) {
+				return s.Kick(context.Background(), "853c80ef-3c37-49fd-aa49-938b674adae6", "")
+			},
+			command:  "/kick 853c80ef-3c37-49fd-aa49-938b674adae6",
+			response: "Kicked jeb_: Kicked by an operator",
+			expected: Applied,
+		},
+		{
+			name:     "positive case: kick",
+			run:      func(s *Service) (*Result, error) { return s.Kick(context.Background(), "jeb_", "Restarting") },
+			command:  "/kick jeb_ Restarting",
+			response: "Kicked jeb_: Restarting",
+			expected: Applied,
+		},
+		{
+			name:     "positive case: kick offline player",
+			run:      func(s *Service) (*Result, error) { return s.Kick(context.Background(), "jeb_", "") },
+			command:  "/kick jeb_",
+			response: "No player was found",
+			expected: NotFound,
+		},
+		{
+			name:     "negative case: unexpected output",
+			run:      func(s *Service) (*Result, error) { return s.Op(context.Background(), "jeb_") },
+			command:  "/op jeb_",
+			response: "Unknown or incomplete command, see below for error",
+			err:      &minecraft.UnexpectedOutputError{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockCommander{responses: map[string]string{tt.command: tt.response}}
+
+			actual, err := tt.run(New(m))
+
+			assert.Equal(t, []string{tt.command}, m.commands)
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, &Result{Outcome: tt.expected, Response: tt.response}, actual)
+			} else {
+				assert.IsType(t, tt.err, err)
+				assert.Nil(t, actual)
+			}
+		})
+	}
+}
+
+func TestService_invalidTarget(t *testing.T) {
+	cases := []struct {
+		name string
+		run  func(s *Service) (*Result, error)
+	}{
+		{
+			name: "negative case: whitelist add selector",
+			run:  func(s *Service) (*Result, error) { return s.WhitelistAdd(context.Background(), "@a") },
+		},
+		{
+			name: "negative case: whitelist remove empty",
+			run:  func(s *Service) (*Result, error) { return s.WhitelistRemove(context.Background(), "") },
+		},
+		{
+			name: "negative case: op selector",
+			run:  func(s *Service) (*Result, error) { return s.Op(context.Background(), "@a") },
+		},
+		{
+			name: "negative case: op extra arguments",
+			run:  func(s *Service) (*Result, error) { return s.Op(context.Background(), "jeb_ extra") },
+		},
+		{
+			name: "negative case: deop too long",
+			run:  func(s *Service) (*Result, error) { return s.Deop(context.Background(), "abcdefghijklmnopq") },
+		},
+		{
+			name: "negative case: ban line break",
+			run:  func(s *Service) (*Result, error) { return s.Ban(context.Background(), "jeb_\n/op @a", "") },
+		},
+		{
+			name: "negative case: ban ip selector",
+			run:  func(s *Service) (*Result, error) { return s.BanIP(context.Background(), "@r", "") },
+		},
+		{
+			name: "negative case: ban ip malformed",
+			run:  func(s *Service) (*Result, error) { return s.BanIP(context.Background(), "999.0.0.1", "") },
+		},
+		{
+			name: "negative case: pardon selector",
+			run:  func(s *Service) (*Result, error) { return s.Pardon(context.Background(), "@p[name=jeb_]") },
+		},
+		{
+			name: "negative case: pardon ip player",
+			run:  func(s *Service) (*Result, error) { return s.PardonIP(context.Background(), "jeb_") },
+		},
+		{
+			name: "negative case: kick selector",
+			run:  func(s *Service) (*Result, error) { return s.Kick(context.Background(), "@e", "") },
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockCommander)
+
+			actual, err := tt.run(New(m))
+
+			assert.ErrorIs(t, err, ErrInvalidTarget)
+			assert.Nil(t, actual)
+			assert.Empty(t, m.commands)
+		})
+	}
+}
+
+func TestService_lists(t *testing.T) {
+	m := &mockCommander{responses: map[string]string{
+		"/list uuids":     "There are 1 of a max of 20 players online: jeb_ (853c80ef-3c37-49fd-aa49-938b674adae6)",
+		"/whitelist list": "There are 2 whitelisted player(s): jeb_, Notch",
+		"/banlist":        "There are 1 ban(s):Notch was banned by Server: Banned by an operator.",
+	}}
+	s := New(m)
+
+	online, err := s.Online(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []minecraft.Player{{Name: "jeb_", UUID: "853c80ef-3c37-49fd-aa49-938b674adae6"}}, online.Players)
+
+	whitelist, err := s.Whitelist(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"jeb_", "Notch"}, whitelist)
+
+	bans, err := s.Bans(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []minecraft.Ban{{Target: "Notch", Source: "Server", Reason: "Banned by an operator."}}, bans)
+}
+
+func TestService_commandError(t *testing.T) {
+	s := New(&mockCommander{})
+
+	_, err := s.Online(context.Background())
+	assert.EqualError(t, err, "unknown command")
+
+	_, err = s.Whitelist(context.Background())
+	assert.Error(t, err)
+
+	_, err = s.Bans(context.Background())
+	assert.Error(t, err)
+
+	_, err = s.Kick(context.Background(), "jeb_", "")
+	assert.Error(t, err)
+}
+
+func TestOutcome_String(t *testing.T) {
+	assert.Equal(t, "applied", Applied.String())
+	assert.Equal(t, "unchanged", Unchanged.String())
+	assert.Equal(t, "not found", NotFound.String())
+	assert.Equal(t, "invalid", Invalid.String())
+	assert.Equal(t, "unknown", Outcome(42).String())
+}
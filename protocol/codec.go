@@ -0,0 +1,102 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package protocol
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// Encoder writes packets to an output stream.
+type Encoder struct {
+	w io.Writer
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the packet in a single call to the underlying writer.
+func (e *Encoder) Encode(p *Packet) error {
+	// NOTE: prevent split packets by writing the whole frame at once
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	if _, err := e.w.Write(b); err != nil {
+		return &PacketError{Op: "encode", Err: err}
+	}
+
+	return nil
+}
+
+// DefaultMaxLength is the default largest packet length a Decoder accepts.
+// It leaves room for servers that do not split responses at 4096 bytes.
+const DefaultMaxLength = 1 << 20
+
+// Decoder reads packets from an input stream.
+type Decoder struct {
+	r         io.Reader
+	maxLength int
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r, maxLength: DefaultMaxLength}
+}
+
+// SetMaxLength sets the largest packet length the decoder accepts, excluding
+// the length field itself. Longer packets are rejected with ErrInvalidLength
+// before anything is allocated. If n is not positive, DefaultMaxLength is
+// used.
+func (d *Decoder) SetMaxLength(n int) {
+	if n <= 0 {
+		n = DefaultMaxLength
+	}
+
+	d.maxLength = n
+}
+
+// Decode reads the next packet from the underlying reader.
+func (d *Decoder) Decode(p *Packet) error {
+	var l int32
+	if err := binary.Read(d.r, binary.LittleEndian, &l); err != nil {
+		return &PacketError{Op: "decode", Err: err}
+	}
+
+	// NOTE: the length comes from the peer, so bound it before allocating
+	n := int(l)
+	if n < MinLength || n > d.maxLength {
+		return &PacketError{Op: "decode", Err: ErrInvalidLength}
+	}
+
+	buf := make([]byte, 4+n)
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(l))
+	if _, err := io.ReadFull(d.r, buf[4:]); err != nil {
+		return &PacketError{Op: "decode", Err: err}
+	}
+
+	if err := p.UnmarshalBinary(buf); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package protocol
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoder_Encode(t *testing.T) {
+	type Case struct {
+		name        string
+		packet      *Packet
+		expected    []byte
+		expectedErr error
+	}
+
+	cases := []Case{}
+
+	for _, c := range packetCases {
+		cases = append(cases, Case{
+			name:        c.name,
+			packet:      c.packet,
+			expected:    c.raw,
+			expectedErr: nil,
+		})
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := NewEncoder(buf).Encode(tt.packet)
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, buf.Bytes())
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	type Case struct {
+		name        string
+		raw         []byte
+		expected    *Packet
+		expectedErr error
+	}
+
+	cases := []Case{}
+
+	for _, c := range packetCases {
+		cases = append(cases, Case{
+			name:        c.name,
+			raw:         c.raw,
+			expected:    c.packet,
+			expectedErr: nil,
+		})
+	}
+
+	cases = append(cases, []Case{
+		{
+			name:        "negative case: invalid length",
+			raw:         []byte{0x02, 0x00, 0x00, 0x00, 0x00, 0x00},
+			expected:    nil,
+			expectedErr: &PacketError{Op: "decode", Err: ErrInvalidLength},
+		},
+		{
+			name:        "negative case: length near max int32",
+			raw:         []byte{0xFE, 0xFF, 0xFF, 0x7F, 0x00, 0x00, 0x00, 0x00},
+			expected:    nil,
+			expectedErr: &PacketError{Op: "decode", Err: ErrInvalidLength},
+		},
+		{
+			name:        "negative case: negative length",
+			raw:         []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0x00, 0x00},
+			expected:    nil,
+			expectedErr: &PacketError{Op: "decode", Err: ErrInvalidLength},
+		},
+		{
+			name:        "negative case: length above default max",
+			raw:         []byte{0x01, 0x00, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00},
+			expected:    nil,
+			expectedErr: &PacketError{Op: "decode", Err: ErrInvalidLength},
+		},
+		{
+			name:        "negative case: unexpected EOF",
+			raw:         []byte{0x0A, 0x00, 0x00, 0x00, 0x40, 0xE2, 0x01, 0x00},
+			expected:    nil,
+			expectedErr: &PacketError{Op: "decode", Err: io.ErrUnexpectedEOF},
+		},
+	}...)
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(tt.raw)
+
+			packet := new(Packet)
+			err := NewDecoder(buf).Decode(packet)
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, packet)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestDecoder_SetMaxLength(t *testing.T) {
+	cases := []struct {
+		name        string
+		maxLength   int
+		expectedErr error
+	}{
+		{
+			name:      "positive case: at max",
+			maxLength: MinLength,
+		},
+		{
+			name:        "negative case: above max",
+			maxLength:   MinLength - 1,
+			expectedErr: &PacketError{Op: "decode", Err: ErrInvalidLength},
+		},
+		{
+			name:      "positive case: default",
+			maxLength: 0,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := NewDecoder(bytes.NewReader([]byte{0x0A, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}))
+			dec.SetMaxLength(tt.maxLength)
+
+			err := dec.Decode(new(Packet))
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode_stream(t *testing.T) {
+	buf := new(bytes.Buffer)
+	for _, c := range packetCases {
+		buf.Write(c.raw)
+	}
+
+	dec := NewDecoder(buf)
+	for _, c := range packetCases {
+		packet := new(Packet)
+		err := dec.Decode(packet)
+
+		assert.NoError(t, err)
+		assert.Equal(t, c.packet, packet)
+	}
+
+	err := dec.Decode(new(Packet))
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func Test_codec_net(t *testing.T) {
+	type Case struct {
+		name      string
+		packet    *Packet
+		clientErr error
+		serverErr error
+	}
+
+	cases := []Case{}
+
+	for _, c := range packetCases {
+		cases = append(cases, Case{
+			name:      c.name,
+			packet:    c.packet,
+			clientErr: nil,
+			serverErr: nil,
+		})
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, clt := net.Pipe()
+			defer clt.Close()
+
+			errCh := make(chan error, 1)
+			defer close(errCh)
+
+			packetCh := make(chan *Packet, 1)
+			defer close(packetCh)
+
+			go func() {
+				defer srv.Close()
+
+				packet := new(Packet)
+				// NOTE: receive packet
+				if err := NewDecoder(srv).Decode(packet); err != nil {
+					errCh <- err
+					packetCh <- nil
+					return
+				}
+
+				errCh <- nil
+				packetCh <- packet
+			}()
+
+			// NOTE: send packet
+			cltErr := NewEncoder(clt).Encode(tt.packet)
+
+			if tt.clientErr == nil {
+				assert.NoError(t, cltErr)
+			} else {
+				assert.Error(t, cltErr)
+				assert.Equal(t, tt.clientErr, cltErr)
+			}
+
+			srvErr := <-errCh
+			srvPacket := <-packetCh
+			if tt.serverErr == nil {
+				assert.NoError(t, srvErr)
+				assert.Equal(t, tt.packet, srvPacket)
+			} else {
+				assert.Error(t, srvErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package protocol implements the RCON wire format shared by Minecraft and
+// Source dedicated servers.
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Type
+type PacketType int32
+
+// Minecraft
+const (
+	AuthRequestType     = PacketType(3)
+	AuthResponseType    = PacketType(2)
+	CommandRequestType  = PacketType(2)
+	CommandResponseType = PacketType(0)
+	DummyRequestType    = PacketType(100)
+
+	// MaxRequestPayloadSize is the largest payload a Minecraft server accepts
+	// in a request.
+	MaxRequestPayloadSize = 1446
+)
+
+// Source
+const (
+	ServerdataAuth          = PacketType(3)
+	ServerdataAuthResponse  = PacketType(2)
+	ServerdataExecCommand   = PacketType(2)
+	ServerdataResponseValue = PacketType(0)
+
+	// ServerdataMaxPayloadSize is the largest payload of a Source packet,
+	// whose length may not exceed 4096.
+	ServerdataMaxPayloadSize = 4096 - MinLength
+)
+
+const (
+	// MinLength is the length of a packet with an empty payload.
+	MinLength = 4 + 4 + 1 + 1
+
+	// Redacted replaces sensitive payloads in String and GoString.
+	Redacted = "<redacted>"
+)
+
+// Packet
+type Packet struct {
+	RequestId  int32
+	PacketType PacketType
+	Payload    []byte
+}
+
+func NewPacket(id int32, typ PacketType, payload []byte) *Packet {
+	return &Packet{
+		RequestId:  id,
+		PacketType: typ,
+		Payload:    payload,
+	}
+}
+
+func (p *Packet) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("Length: %d, RequestId: %d, Type: %d, Payload: %s", p.Length(), p.RequestId, p.PacketType, p.redactedPayload())
+}
+
+func (p *Packet) GoString() string {
+	if p == nil {
+		return "(*protocol.Packet)(nil)"
+	}
+
+	payload := "nil"
+	if p.Payload != nil {
+		payload = fmt.Sprintf("[]byte(%q)", p.redactedPayload())
+	}
+
+	return fmt.Sprintf("&protocol.Packet{RequestId:%d, PacketType:%d, Payload:%s}", p.RequestId, p.PacketType, payload)
+}
+
+// redactedPayload returns the payload for display. Auth payloads carry the
+// server password and are never shown.
+func (p *Packet) redactedPayload() string {
+	if p.Payload == nil {
+		return "<nil>"
+	}
+
+	if p.PacketType == AuthRequestType {
+		return Redacted
+	}
+
+	return string(p.Payload)
+}
+
+// Length returns the value of the length field, which excludes the field itself.
+func (p *Packet) Length() int {
+	// Request ID                :                4 byte
+	// Packet Type               :                4 byte
+	// Payload (NULL-terminated) : len(payload) + 1 byte
+	// 1-byte Pad                :                1 byte
+	return 4 + 4 + (len(p.Payload) + 1) + 1
+}
+
+// MarshalBinary returns the packet as a length-prefixed frame.
+func (p *Packet) MarshalBinary() ([]byte, error) {
+	l := p.Length()
+	data := make([]byte, 4+l)
+
+	binary.LittleEndian.PutUint32(data[0:4], uint32(l))
+	binary.LittleEndian.PutUint32(data[4:8], uint32(p.RequestId))
+	binary.LittleEndian.PutUint32(data[8:12], uint32(p.PacketType))
+	copy(data[12:], p.Payload)
+
+	// NOTE: payload is NULL-terminated and packet has 1-byte pad
+	data[len(data)-2] = 0x00
+	data[len(data)-1] = 0x00
+
+	return data, nil
+}
+
+// UnmarshalBinary parses a length-prefixed frame into the packet.
+func (p *Packet) UnmarshalBinary(data []byte) error {
+	if len(data) < 4+MinLength {
+		return &PacketError{Op: "unmarshal", Err: ErrShortPacket}
+	}
+
+	l := int32(binary.LittleEndian.Uint32(data[0:4]))
+	if l < MinLength {
+		return &PacketError{Op: "unmarshal", Err: ErrInvalidLength}
+	}
+
+	if int(l) != len(data)-4 {
+		return &PacketError{Op: "unmarshal", Err: ErrInvalidLength}
+	}
+
+	p.RequestId = int32(binary.LittleEndian.Uint32(data[4:8]))
+	p.PacketType = PacketType(binary.LittleEndian.Uint32(data[8:12]))
+
+	payload := data[12 : len(data)-2]
+	p.Payload = make([]byte, len(payload))
+	copy(p.Payload, payload)
+
+	return nil
+}
@@ -20,10 +20,10 @@
 
 //go:build !e2e
 
-package rcon
+package protocol
 
 import (
-	"bytes"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -31,15 +31,15 @@ import (
 
 var packetCases = []struct {
 	name   string
-	packet *packet
+	packet *Packet
 	raw    []byte
 }{
 	{
 		name: "positive case: Auth Request",
-		packet: &packet{
-			requestId:  123456,
-			packetType: authRequestType,
-			payload:    []byte("auth"),
+		packet: &Packet{
+			RequestId:  123456,
+			PacketType: AuthRequestType,
+			Payload:    []byte("auth"),
 		},
 		raw: []byte{
 			// Length: 14
@@ -56,10 +56,10 @@ var packetCases = []struct {
 	},
 	{
 		name: "positive case: Auth Response",
-		packet: &packet{
-			requestId:  123456,
-			packetType: authResponseType,
-			payload:    []byte(""),
+		packet: &Packet{
+			RequestId:  123456,
+			PacketType: AuthResponseType,
+			Payload:    []byte(""),
 		},
 		raw: []byte{
 			// Length: 14
@@ -76,10 +76,10 @@ var packetCases = []struct {
 	},
 	{
 		name: "positive case: Command Request",
-		packet: &packet{
-			requestId:  123456,
-			packetType: commandRequestType,
-			payload:    []byte("command"),
+		packet: &Packet{
+			RequestId:  123456,
+			PacketType: CommandRequestType,
+			Payload:    []byte("command"),
 		},
 		raw: []byte{
 			// Length: 14
@@ -96,10 +96,10 @@ var packetCases = []struct {
 	},
 	{
 		name: "positive case: Command Response",
-		packet: &packet{
-			requestId:  123456,
-			packetType: commandResponseType,
-			payload:    []byte("response"),
+		packet: &Packet{
+			RequestId:  123456,
+			PacketType: CommandResponseType,
+			Payload:    []byte("response"),
 		},
 		raw: []byte{
 			// Length: 18
@@ -116,10 +116,10 @@ var packetCases = []struct {
 	},
 	{
 		name: "positive case: Dummy Request",
-		packet: &packet{
-			requestId:  123456,
-			packetType: dummyRequestType,
-			payload:    []byte("dummy request"),
+		packet: &Packet{
+			RequestId:  123456,
+			PacketType: DummyRequestType,
+			Payload:    []byte("dummy request"),
 		},
 		raw: []byte{
 			// Length: 23
@@ -136,10 +136,10 @@ var packetCases = []struct {
 	},
 	{
 		name: "positive case: Unknown Response",
-		packet: &packet{
-			requestId:  123456,
-			packetType: commandResponseType,
-			payload:    []byte("Unknown request 64"),
+		packet: &Packet{
+			RequestId:  123456,
+			PacketType: CommandResponseType,
+			Payload:    []byte("Unknown request 64"),
 		},
 		raw: []byte{
 			// Length: 28
@@ -156,13 +156,13 @@ var packetCases = []struct {
 	},
 }
 
-func Test_newPacket(t *testing.T) {
+func TestNewPacket(t *testing.T) {
 	type Case struct {
 		name       string
 		requestId  int32
-		packetType packetType
+		packetType PacketType
 		payload    []byte
-		expected   *packet
+		expected   *Packet
 	}
 
 	cases := []Case{}
@@ -170,29 +170,29 @@ func Test_newPacket(t *testing.T) {
 	for _, c := range packetCases {
 		cases = append(cases, Case{
 			name:       c.name,
-			requestId:  c.packet.requestId,
-			packetType: c.packet.packetType,
-			payload:    c.packet.payload,
-			expected: &packet{
-				requestId:  c.packet.requestId,
-				packetType: c.packet.packetType,
-				payload:    c.packet.payload,
+			requestId:  c.packet.RequestId,
+			packetType: c.packet.PacketType,
+			payload:    c.packet.Payload,
+			expected: &Packet{
+				RequestId:  c.packet.RequestId,
+				PacketType: c.packet.PacketType,
+				Payload:    c.packet.Payload,
 			},
 		})
 	}
 
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			actual := newPacket(tt.requestId, tt.packetType, tt.payload)
+			actual := NewPacket(tt.requestId, tt.packetType, tt.payload)
 			assert.Equal(t, tt.expected, actual)
 		})
 	}
 }
 
-func Test_packet_encode(t *testing.T) {
+func TestPacket_MarshalBinary(t *testing.T) {
 	type Case struct {
 		name        string
-		packet      *packet
+		packet      *Packet
 		expected    []byte
 		expectedErr error
 	}
@@ -210,12 +210,11 @@ func Test_packet_encode(t *testing.T) {
 
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			buf := new(bytes.Buffer)
-			err := tt.packet.encode(buf)
+			actual, err := tt.packet.MarshalBinary()
 
 			if tt.expectedErr == nil {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expected, buf.Bytes())
+				assert.Equal(t, tt.expected, actual)
 			} else {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectedErr, err)
@@ -224,11 +223,11 @@ func Test_packet_encode(t *testing.T) {
 	}
 }
 
-func Test_packet_decode(t *testing.T) {
+func TestPacket_UnmarshalBinary(t *testing.T) {
 	type Case struct {
 		name        string
 		raw         []byte
-		expected    *packet
+		expected    *Packet
 		expectedErr error
 	}
 
@@ -243,12 +242,36 @@ func Test_packet_decode(t *testing.T) {
 		})
 	}
 
+	cases = append(cases, []Case{
+		{
+			name:        "negative case: short packet",
+			raw:         []byte{0x0A, 0x00, 0x00, 0x00, 0x40, 0xE2, 0x01, 0x00},
+			expected:    nil,
+			expectedErr: &PacketError{Op: "unmarshal", Err: ErrShortPacket},
+		},
+		{
+			name: "negative case: length mismatch",
+			raw: []byte{
+				// Length: 11
+				0x0B, 0x00, 0x00, 0x00,
+				// Request ID: 123456
+				0x40, 0xE2, 0x01, 0x00,
+				// Packet Type: Auth Response (=2)
+				0x02, 0x00, 0x00, 0x00,
+				// Payload (NULL-terminated): ""
+				0x00,
+				// 1-byte Pad
+				0x00,
+			},
+			expected:    nil,
+			expectedErr: &PacketError{Op: "unmarshal", Err: ErrInvalidLength},
+		},
+	}...)
+
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			buf := bytes.NewBuffer(tt.raw)
-
-			packet := new(packet)
-			err := packet.decode(buf)
+			packet := new(Packet)
+			err := packet.UnmarshalBinary(tt.raw)
 
 			if tt.expectedErr == nil {
 				assert.NoError(t, err)
@@ -261,69 +284,72 @@ func Test_packet_decode(t *testing.T) {
 	}
 }
 
-func Test_packet_net(t *testing.T) {
-	type Case struct {
-		name      string
-		packet    *packet
-		clientErr error
-		serverErr error
+func TestPacket_String(t *testing.T) {
+	cases := []struct {
+		name     string
+		packet   *Packet
+		expected string
+	}{
+		{
+			name:     "positive case: Auth Request",
+			packet:   &Packet{RequestId: 123456, PacketType: AuthRequestType, Payload: []byte("minecraft")},
+			expected: "Length: 19, RequestId: 123456, Type: 3, Payload: <redacted>",
+		},
+		{
+			name:     "positive case: Command Request",
+			packet:   &Packet{RequestId: 123456, PacketType: CommandRequestType, Payload: []byte("/seed")},
+			expected: "Length: 15, RequestId: 123456, Type: 2, Payload: /seed",
+		},
+		{
+			name:     "positive case: nil payload",
+			packet:   &Packet{RequestId: 123456, PacketType: AuthRequestType, Payload: nil},
+			expected: "Length: 10, RequestId: 123456, Type: 3, Payload: <nil>",
+		},
+		{
+			name:     "positive case: nil",
+			packet:   nil,
+			expected: "<nil>",
+		},
 	}
 
-	cases := []Case{}
-
-	for _, c := range packetCases {
-		cases = append(cases, Case{
-			name:      c.name,
-			packet:    c.packet,
-			clientErr: nil,
-			serverErr: nil,
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.packet.String())
 		})
 	}
+}
+
+func TestPacket_GoString(t *testing.T) {
+	cases := []struct {
+		name     string
+		packet   *Packet
+		expected string
+	}{
+		{
+			name:     "positive case: Auth Request",
+			packet:   &Packet{RequestId: 123456, PacketType: AuthRequestType, Payload: []byte("minecraft")},
+			expected: `&protocol.Packet{RequestId:123456, PacketType:3, Payload:[]byte("<redacted>")}`,
+		},
+		{
+			name:     "positive case: Command Request",
+			packet:   &Packet{RequestId: 123456, PacketType: CommandRequestType, Payload: []byte("/seed")},
+			expected: `&protocol.Packet{RequestId:123456, PacketType:2, Payload:[]byte("/seed")}`,
+		},
+		{
+			name:     "positive case: nil payload",
+			packet:   &Packet{RequestId: 123456, PacketType: CommandRequestType, Payload: nil},
+			expected: `&protocol.Packet{RequestId:123456, PacketType:2, Payload:nil}`,
+		},
+		{
+			name:     "positive case: nil",
+			packet:   nil,
+			expected: `(*protocol.Packet)(nil)`,
+		},
+	}
 
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			srv, clt := pipe()
-			defer clt.Close()
-
-			errCh := make(chan error, 1)
-			defer close(errCh)
-
-			packetCh := make(chan *packet, 1)
-			defer close(packetCh)
-
-			go func() {
-				defer srv.Close()
-
-				packet := new(packet)
-				// NOTE: receive packet
-				if err := packet.decode(srv); err != nil {
-					errCh <- err
-					packetCh <- nil
-					return
-				}
-
-				errCh <- nil
-				packetCh <- packet
-			}()
-
-			// NOTE: send packet
-			cltErr := tt.packet.encode(clt)
-
-			if tt.clientErr == nil {
-				assert.NoError(t, cltErr)
-			} else {
-				assert.Error(t, cltErr)
-				assert.Equal(t, tt.clientErr, cltErr)
-			}
-
-			srvErr := <-errCh
-			srvPacket := <-packetCh
-			if tt.serverErr == nil {
-				assert.NoError(t, srvErr)
-				assert.Equal(t, tt.packet, srvPacket)
-			} else {
-				assert.Error(t, srvErr)
-			}
+			assert.Equal(t, tt.expected, fmt.Sprintf("%#v", tt.packet))
 		})
 	}
 }
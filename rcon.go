@@ -21,9 +21,17 @@
 package rcon
 
 import (
+	"context"
+	"errors"
 	"math/rand"
 	"net"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	liblog "github.com/Aton-Kish/gorcon/log"
+	"github.com/Aton-Kish/gorcon/protocol"
+	"github.com/Aton-Kish/gorcon/rcontrace"
 )
 
 const (
@@ -32,18 +40,89 @@ const (
 	maxResponseLength      = 4 + 4 + (maxResponsePayloadSize + 1) + 1
 )
 
+// ErrBroken is returned by a connection after a request failed or was
+// interrupted in the middle of an exchange, which leaves the protocol stream
+// out of sync.
+var ErrBroken = errors.New("connection is broken")
+
+// RCON is a connection to an RCON server.
+//
+// RCON exposes the underlying net.Conn, and raw reads or writes corrupt the
+// protocol stream. New code should prefer Client; see AsClient.
+//
+// Connections returned by Dial also implement Client, RawConn, AsyncCommander
+// and Streamer. Those are separate interfaces so that other implementations
+// of RCON keep compiling.
 type RCON interface {
 	net.Conn
 
 	Command(command string) (string, error)
 }
 
+// RawConn is implemented by connections that exchange raw packets, such as
+// connections returned by Dial.
+type RawConn interface {
+	// SendPacket writes a single raw packet, such as a keep-alive or a vendor
+	// extension, without interleaving with other requests.
+	SendPacket(p *protocol.Packet) error
+	// ReadPacket reads a single raw packet.
+	ReadPacket() (*protocol.Packet, error)
+	// RoundTrip writes a single raw packet and reads the next packet as its
+	// reply. Unlike SendPacket followed by ReadPacket, no other request can
+	// run in between.
+	RoundTrip(p *protocol.Packet) (*protocol.Packet, error)
+}
+
 type rcon struct {
 	net.Conn
+
+	mu             sync.Mutex
+	enc            *protocol.Encoder
+	dec            *protocol.Decoder
+	stats          stats
+	addr           string
+	logger         liblog.LeveledLogger
+	redactor       Redactor
+	hooks          Hooks
+	handler        CommandFunc
+	hasMiddleware  bool
+	maxCommandSize int
+	broken         atomic.Bool
+	// lastActive is the time of the last packet sent or received, in Unix
+	// nanoseconds
+	lastActive atomic.Int64
+
+	// deadlines set by the caller, restored after an interrupted request
+	dmu           sync.Mutex
+	readDeadline  time.Time
+	writeDeadline time.Time
 }
 
-func Dial(addr string, password string) (RCON, error) {
-	c, err := DialTimeout(addr, password, 0)
+func newRCON(conn net.Conn, opts ...Option) *rcon {
+	o := newOptions(opts...)
+
+	c := &rcon{
+		Conn:           conn,
+		enc:            protocol.NewEncoder(conn),
+		dec:            protocol.NewDecoder(conn),
+		logger:         o.logger,
+		redactor:       o.redactor,
+		hooks:          o.hooks,
+		maxCommandSize: o.maxCommandSize,
+	}
+	c.handler = Chain(o.middlewares...)(c.command)
+	c.hasMiddleware = len(o.middlewares) > 0
+
+	return c
+}
+
+var (
+	_ Client  = (*rcon)(nil)
+	_ RawConn = (*rcon)(nil)
+)
+
+func Dial(addr string, password string, opts ...Option) (RCON, error) {
+	c, err := DialTimeout(addr, password, 0, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -51,100 +130,488 @@ func Dial(addr string, password string) (RCON, error) {
 	return c, nil
 }
 
-func DialTimeout(addr string, password string, timeout time.Duration) (RCON, error) {
-	conn, err := net.DialTimeout("tcp", addr, timeout)
+func DialTimeout(addr string, password string, timeout time.Duration, opts ...Option) (RCON, error) {
+	d := &net.Dialer{Timeout: timeout}
+	c, err := dial(context.Background(), d, addr, password, opts...)
 	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func DialContext(ctx context.Context, addr string, password string, opts ...Option) (RCON, error) {
+	d := new(net.Dialer)
+	c, err := dial(ctx, d, addr, password, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func dial(ctx context.Context, d *net.Dialer, addr string, password string, opts ...Option) (*rcon, error) {
+	o := newOptions(opts...)
+	logger := resolveLogger(ctx, o.logger)
+
+	conn, err := dialConn(ctx, d, addr)
+	if err != nil {
+		o.hooks.DialFailed(addr, err)
 		err = &RCONError{Op: "dial", Err: err}
-		logger.Println("failed to dial", "func", getFuncName(), "error", err)
+		logger.Log(liblog.LevelError, "failed to dial", liblog.String("func", getFuncName()), liblog.Err(err))
 		return nil, err
 	}
 
-	c := &rcon{conn}
-	if err := c.auth(password); err != nil {
+	c := newRCON(conn, opts...)
+	c.addr = addr
+	if err := c.auth(ctx, password); err != nil {
 		defer c.Close()
 		err = &RCONError{Op: "dial", Err: err}
-		logger.Println("failed to dial", "func", getFuncName(), "error", err)
+		logger.Log(liblog.LevelError, "failed to dial", liblog.String("func", getFuncName()), liblog.Err(err))
 		return nil, err
 	}
 
 	return c, nil
 }
 
-func pipe() (*rcon, *rcon) {
+// dialConn connects to addr. When ctx carries a ClientTrace, the host name is
+// resolved here so that DNS and connect events can be reported, and the
+// resolved addresses are dialed in order.
+func dialConn(ctx context.Context, d *net.Dialer, addr string) (net.Conn, error) {
+	trace := rcontrace.ContextClientTrace(ctx)
+	if trace == nil {
+		return d.DialContext(ctx, "tcp", addr)
+	}
+
+	if d.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.Timeout)
+		defer cancel()
+	}
+
+	targets := []string{addr}
+	if host, port, err := net.SplitHostPort(addr); err == nil && host != "" && net.ParseIP(host) == nil {
+		if trace.DNSStart != nil {
+			trace.DNSStart(rcontrace.DNSStartInfo{Host: host})
+		}
+
+		ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if trace.DNSDone != nil {
+			trace.DNSDone(rcontrace.DNSDoneInfo{Addrs: ips, Err: err})
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		targets = targets[:0]
+		for _, ip := range ips {
+			targets = append(targets, net.JoinHostPort(ip.String(), port))
+		}
+	}
+
+	var err error
+	for _, target := range targets {
+		if trace.ConnectStart != nil {
+			trace.ConnectStart("tcp", target)
+		}
+
+		var conn net.Conn
+		conn, err = d.DialContext(ctx, "tcp", target)
+		if trace.ConnectDone != nil {
+			trace.ConnectDone("tcp", target, err)
+		}
+		if err == nil {
+			return conn, nil
+		}
+	}
+
+	return nil, err
+}
+
+func pipe(opts ...Option) (*rcon, *rcon) {
 	srv, clt := net.Pipe()
-	return &rcon{srv}, &rcon{clt}
+	return newRCON(srv), newRCON(clt, opts...)
 }
 
-func (c *rcon) auth(password string) error {
+func (c *rcon) auth(ctx context.Context, password string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	logger := c.getLogger(ctx)
+
+	trace := rcontrace.ContextClientTrace(ctx)
+
 	id := rand.Int31()
-	res, err := c.request(id, authRequestType, []byte(password))
+	res, _, err := c.request(ctx, id, protocol.AuthRequestType, []byte(password))
 	if err != nil {
+		if trace != nil && trace.AuthDone != nil {
+			trace.AuthDone(err)
+		}
+		c.hooks.AuthFailed(c.addr, err)
 		err = &RCONError{Op: "auth", Err: err}
-		logger.Println("failed to auth", "func", getFuncName(), "error", err)
+		logger.Log(liblog.LevelError, "failed to auth", liblog.String("func", getFuncName()), liblog.Err(err))
 		return err
 	}
 
-	if res.requestId != id || res.requestId == unauthorizedRequestID {
+	if res.RequestId != id || res.RequestId == unauthorizedRequestID {
 		err = &RCONError{Op: "auth"}
-		logger.Println("failed to auth", "func", getFuncName(), "error", err)
+		if trace != nil && trace.AuthDone != nil {
+			trace.AuthDone(err)
+		}
+		c.hooks.AuthFailed(c.addr, err)
+		logger.Log(liblog.LevelError, "failed to auth", liblog.String("func", getFuncName()), liblog.Err(err))
 		return err
 	}
 
+	if trace != nil && trace.AuthDone != nil {
+		trace.AuthDone(nil)
+	}
+
 	return nil
 }
 
 func (c *rcon) Command(command string) (string, error) {
+	return c.CommandContext(context.Background(), command)
+}
+
+// CommandContext is like Command, but aborts the request when ctx is done and
+// logs to the logger carried by ctx, if any.
+func (c *rcon) CommandContext(ctx context.Context, command string) (string, error) {
+	return c.handler(ctx, command)
+}
+
+// command sends the command to the server. It is the innermost CommandFunc of
+// the middleware chain.
+func (c *rcon) command(ctx context.Context, command string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	logger := c.getLogger(ctx)
+	trace := rcontrace.ContextClientTrace(ctx)
+
+	if trace != nil && trace.CommandStart != nil {
+		trace.CommandStart(rcontrace.CommandStartInfo{Command: c.redact(command)})
+	}
+
+	start := time.Now()
+
+	var (
+		res           *protocol.Packet
+		continuations int
+	)
+
 	id := rand.Int31()
-	res, err := c.request(id, commandRequestType, []byte(command))
+	err := c.validate(command)
+	if err == nil {
+		res, continuations, err = c.request(ctx, id, protocol.CommandRequestType, []byte(command))
+	}
 	if err != nil {
+		c.stats.failures.Add(1)
 		err = &RCONError{Op: "command", Err: err}
-		logger.Println("failed to command", "func", getFuncName(), "error", err)
+		if trace != nil && trace.CommandDone != nil {
+			trace.CommandDone(rcontrace.CommandDoneInfo{Command: c.redact(command), Duration: time.Since(start), Continuations: continuations, Err: err})
+		}
+		c.hooks.CommandDone(c.addr, time.Since(start), 0, continuations, err)
+		logger.Log(liblog.LevelError, "failed to command", liblog.String("func", getFuncName()), liblog.String("command", c.redact(command)), liblog.Err(err))
 		return "", err
 	}
 
-	payload := string(res.payload)
+	c.stats.commands.Add(1)
+	if trace != nil && trace.CommandDone != nil {
+		trace.CommandDone(rcontrace.CommandDoneInfo{Command: c.redact(command), Duration: time.Since(start), Size: len(res.Payload), Continuations: continuations})
+	}
+	c.hooks.CommandDone(c.addr, time.Since(start), len(res.Payload), continuations, nil)
+	payload := string(res.Payload)
 
 	return payload, nil
 }
 
-func (c *rcon) request(id int32, typ packetType, payload []byte) (*packet, error) {
-	req := newPacket(id, typ, payload)
-	if err := req.encode(c); err != nil {
-		logger.Println("failed to request", "func", getFuncName(), "error", err)
+func (c *rcon) SendPacket(p *protocol.Packet) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.encode(context.Background(), p); err != nil {
+		err = &RCONError{Op: "send", Err: err}
+		c.getLogger(context.Background()).Log(liblog.LevelError, "failed to send packet", liblog.String("func", getFuncName()), liblog.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+func (c *rcon) ReadPacket() (*protocol.Packet, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p := new(protocol.Packet)
+	if err := c.decode(context.Background(), p, false); err != nil {
+		err = &RCONError{Op: "read", Err: err}
+		c.getLogger(context.Background()).Log(liblog.LevelError, "failed to read packet", liblog.String("func", getFuncName()), liblog.Err(err))
 		return nil, err
 	}
 
-	res := new(packet)
-	if err := res.decode(c); err != nil {
-		logger.Println("failed to request", "func", getFuncName(), "error", err)
+	return p, nil
+}
+
+func (c *rcon) RoundTrip(p *protocol.Packet) (*protocol.Packet, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.encode(context.Background(), p); err != nil {
+		err = &RCONError{Op: "round trip", Err: err}
+		c.getLogger(context.Background()).Log(liblog.LevelError, "failed to send packet", liblog.String("func", getFuncName()), liblog.Err(err))
 		return nil, err
 	}
 
-	if res.length() < maxResponseLength {
-		return res, nil
+	res := new(protocol.Packet)
+	if err := c.decode(context.Background(), res, false); err != nil {
+		err = &RCONError{Op: "round trip", Err: err}
+		c.getLogger(context.Background()).Log(liblog.LevelError, "failed to read packet", liblog.String("func", getFuncName()), liblog.Err(err))
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// request sends a request and reads its response, returning the number of
+// continuation packets the response was split into.
+func (c *rcon) request(ctx context.Context, id int32, typ protocol.PacketType, payload []byte) (*protocol.Packet, int, error) {
+	logger := c.getLogger(ctx)
+
+	if err := ctx.Err(); err != nil {
+		logger.Log(liblog.LevelError, "failed to request", liblog.String("func", getFuncName()), liblog.Err(err))
+		return nil, 0, &unsentError{err: err}
+	}
+
+	stop := c.watch(ctx)
+	res, continuations, err := c.exchange(ctx, id, typ, payload)
+	stop(err)
+
+	if err != nil {
+		// NOTE: the rest of the exchange may still be in flight
+		c.breakConn()
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+
+		logger.Log(liblog.LevelError, "failed to request", liblog.String("func", getFuncName()), liblog.Err(err))
+		return nil, continuations, err
+	}
+
+	return res, continuations, nil
+}
+
+func (c *rcon) exchange(ctx context.Context, id int32, typ protocol.PacketType, payload []byte) (*protocol.Packet, int, error) {
+	req := protocol.NewPacket(id, typ, payload)
+	if err := c.encode(ctx, req); err != nil {
+		return nil, 0, err
+	}
+
+	if trace := rcontrace.ContextClientTrace(ctx); trace != nil && trace.AuthSent != nil && typ == protocol.AuthRequestType {
+		trace.AuthSent()
+	}
+
+	res := new(protocol.Packet)
+	if err := c.decode(ctx, res, false); err != nil {
+		return nil, 0, err
+	}
+
+	if res.Length() < maxResponseLength {
+		return res, 0, nil
 	}
 
 	// NOTE: dummy request
-	dummy := newPacket(id, dummyRequestType, []byte{})
-	if err := dummy.encode(c); err != nil {
-		logger.Println("failed to request", "func", getFuncName(), "error", err)
-		return nil, err
+	dummy := protocol.NewPacket(id, protocol.DummyRequestType, []byte{})
+	if err := c.encode(ctx, dummy); err != nil {
+		return nil, 0, err
 	}
 
+	continuations := 0
 	for {
-		more := new(packet)
-		if err := more.decode(c); err != nil {
-			logger.Println("failed to request", "func", getFuncName(), "error", err)
-			return nil, err
+		more := new(protocol.Packet)
+		if err := c.decode(ctx, more, true); err != nil {
+			return nil, continuations, err
 		}
 
-		if string(more.payload) == "Unknown request 64" {
+		if string(more.Payload) == "Unknown request 64" {
 			// NOTE: termination
 			break
 		}
 
-		res.payload = append(res.payload, more.payload...)
+		res.Payload = append(res.Payload, more.Payload...)
+		continuations++
 	}
 
-	return res, nil
+	return res, continuations, nil
+}
+
+// watch interrupts in-flight I/O once ctx is done. The returned function must
+// be called with the outcome when the I/O has finished. If the I/O was
+// interrupted and failed, the stream is out of sync and the connection is
+// broken; otherwise the deadlines set by the caller are restored.
+func (c *rcon) watch(ctx context.Context) func(err error) {
+	if ctx.Done() == nil {
+		return func(error) {}
+	}
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	interrupted := false
+	go func() {
+		defer close(exited)
+
+		select {
+		case <-ctx.Done():
+			// NOTE: unblock pending reads and writes
+			interrupted = true
+			_ = c.Conn.SetDeadline(time.Unix(1, 0))
+		case <-done:
+		}
+	}()
+
+	return func(err error) {
+		close(done)
+		<-exited
+
+		if !interrupted {
+			return
+		}
+
+		if err != nil {
+			c.breakConn()
+			return
+		}
+
+		c.restoreDeadlines()
+	}
+}
+
+// breakConn closes the connection after the protocol stream went out of sync,
+// such as after a dead socket or an interrupted request.
+// Later requests fail with ErrBroken.
+func (c *rcon) breakConn() {
+	if c.broken.Swap(true) {
+		return
+	}
+
+	_ = c.Conn.Close()
+	c.getLogger(context.Background()).Log(liblog.LevelWarn, "connection broken", liblog.String("func", getFuncName()), liblog.String("addr", c.addr))
+}
+
+func (c *rcon) restoreDeadlines() {
+	c.dmu.Lock()
+	defer c.dmu.Unlock()
+
+	_ = c.Conn.SetReadDeadline(c.readDeadline)
+	_ = c.Conn.SetWriteDeadline(c.writeDeadline)
+}
+
+// Close closes the connection. Closing a broken connection is a no-op.
+func (c *rcon) Close() error {
+	if c.broken.Swap(true) {
+		return nil
+	}
+
+	return c.Conn.Close()
+}
+
+func (c *rcon) SetDeadline(t time.Time) error {
+	c.dmu.Lock()
+	defer c.dmu.Unlock()
+
+	c.readDeadline, c.writeDeadline = t, t
+	return c.Conn.SetDeadline(t)
+}
+
+func (c *rcon) SetReadDeadline(t time.Time) error {
+	c.dmu.Lock()
+	defer c.dmu.Unlock()
+
+	c.readDeadline = t
+	return c.Conn.SetReadDeadline(t)
+}
+
+func (c *rcon) SetWriteDeadline(t time.Time) error {
+	c.dmu.Lock()
+	defer c.dmu.Unlock()
+
+	c.writeDeadline = t
+	return c.Conn.SetWriteDeadline(t)
+}
+
+func (c *rcon) encode(ctx context.Context, p *protocol.Packet) error {
+	if c.broken.Load() {
+		return ErrBroken
+	}
+
+	err := c.enc.Encode(p)
+	if trace := rcontrace.ContextClientTrace(ctx); trace != nil && trace.PacketWritten != nil {
+		trace.PacketWritten(rcontrace.PacketInfo{RequestId: p.RequestId, PacketType: p.PacketType, Length: p.Length(), Err: err})
+	}
+	if err != nil {
+		return err
+	}
+
+	c.stats.packetsSent.Add(1)
+	c.lastActive.Store(time.Now().UnixNano())
+	c.logPacket(ctx, "send", p, false)
+
+	return nil
+}
+
+func (c *rcon) decode(ctx context.Context, p *protocol.Packet, continuation bool) error {
+	if c.broken.Load() {
+		return ErrBroken
+	}
+
+	err := c.dec.Decode(p)
+	if trace := rcontrace.ContextClientTrace(ctx); trace != nil && trace.PacketRead != nil {
+		info := rcontrace.PacketInfo{Continuation: continuation, Err: err}
+		if err == nil {
+			info.RequestId, info.PacketType, info.Length = p.RequestId, p.PacketType, p.Length()
+		}
+		trace.PacketRead(info)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.stats.packetsReceived.Add(1)
+	c.lastActive.Store(time.Now().UnixNano())
+	c.logPacket(ctx, "receive", p, continuation)
+
+	return nil
+}
+
+func (c *rcon) logPacket(ctx context.Context, direction string, p *protocol.Packet, continuation bool) {
+	logger := c.getLogger(ctx)
+	if !logger.Enabled(liblog.LevelDebug) {
+		return
+	}
+
+	attrs := []liblog.Attr{
+		liblog.String("direction", direction),
+		liblog.Int("id", int(p.RequestId)),
+		liblog.Int("type", int(p.PacketType)),
+		liblog.Int("length", p.Length()),
+	}
+	if continuation {
+		attrs = append(attrs, liblog.Any("continuation", true))
+	}
+
+	logger.Log(liblog.LevelDebug, "packet", attrs...)
+}
+
+func (c *rcon) getLogger(ctx context.Context) liblog.LeveledLogger {
+	return resolveLogger(ctx, c.logger)
+}
+
+// idle returns how long no packet was sent or received.
+func (c *rcon) idle() time.Duration {
+	return time.Since(time.Unix(0, c.lastActive.Load()))
+}
+
+func (c *rcon) Stats() Stats {
+	return c.stats.snapshot()
 }
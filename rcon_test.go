@@ -23,12 +23,17 @@
 package rcon
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"log"
 	"net"
 	"strings"
 	"testing"
 	"time"
 
+	liblog "github.com/Aton-Kish/gorcon/log"
+	"github.com/Aton-Kish/gorcon/protocol"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -81,18 +86,18 @@ func TestDialTimeout(t *testing.T) {
 			errCh := make(chan error, 1)
 			defer close(errCh)
 
-			go func() {
-				addr, err := net.ResolveTCPAddr("tcp", mockAdderss)
-				if err != nil {
-					errCh <- err
-					return
-				}
+			// NOTE: listen before dialing to avoid racing the client
+			addr, err := net.ResolveTCPAddr("tcp", mockAdderss)
+			if err != nil {
+				t.Fatal(err)
+			}
 
-				l, err := net.ListenTCP("tcp", addr)
-				if err != nil {
-					errCh <- err
-					return
-				}
+			l, err := net.ListenTCP("tcp", addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go func() {
 				defer l.Close()
 
 				if err := l.SetDeadline(time.Now().Add(mockTimeout)); err != nil {
@@ -112,19 +117,19 @@ func TestDialTimeout(t *testing.T) {
 				}
 				defer conn.Close()
 
-				req := new(packet)
-				if err := req.decode(conn); err != nil {
+				req := new(protocol.Packet)
+				if err := protocol.NewDecoder(conn).Decode(req); err != nil {
 					errCh <- err
 					return
 				}
 
-				var res *packet
-				if string(req.payload) == mockPassword {
-					res = newPacket(req.requestId, authResponseType, []byte{})
+				var res *protocol.Packet
+				if string(req.Payload) == mockPassword {
+					res = protocol.NewPacket(req.RequestId, protocol.AuthResponseType, []byte{})
 				} else {
-					res = newPacket(unauthorizedRequestID, authResponseType, []byte{})
+					res = protocol.NewPacket(unauthorizedRequestID, protocol.AuthResponseType, []byte{})
 				}
-				if err := res.encode(conn); err != nil {
+				if err := protocol.NewEncoder(conn).Encode(res); err != nil {
 					errCh <- err
 					return
 				}
@@ -186,26 +191,26 @@ func Test_rcon_auth(t *testing.T) {
 			go func() {
 				defer srv.Close()
 
-				req := new(packet)
-				if err := req.decode(srv); err != nil {
+				req := new(protocol.Packet)
+				if err := srv.dec.Decode(req); err != nil {
 					errCh <- err
 					return
 				}
 
-				var res *packet
-				if string(req.payload) == mockPassword {
-					res = newPacket(req.requestId, authResponseType, []byte{})
+				var res *protocol.Packet
+				if string(req.Payload) == mockPassword {
+					res = protocol.NewPacket(req.RequestId, protocol.AuthResponseType, []byte{})
 				} else {
-					res = newPacket(unauthorizedRequestID, authResponseType, []byte{})
+					res = protocol.NewPacket(unauthorizedRequestID, protocol.AuthResponseType, []byte{})
 				}
-				if err := res.encode(srv); err != nil {
+				if err := srv.enc.Encode(res); err != nil {
 					errCh <- err
 				}
 
 				errCh <- nil
 			}()
 
-			cltErr := clt.auth(tt.password)
+			cltErr := clt.auth(context.Background(), tt.password)
 
 			if tt.clientErr == nil {
 				assert.NoError(t, cltErr)
@@ -228,7 +233,7 @@ func Test_rcon_Command(t *testing.T) {
 	cases := []struct {
 		name      string
 		command   string
-		responses []packet
+		responses []protocol.Packet
 		expected  string
 		clientErr error
 		serverErr error
@@ -236,9 +241,9 @@ func Test_rcon_Command(t *testing.T) {
 		{
 			name:    "positive case: non-fragment response",
 			command: "request",
-			responses: []packet{
-				{requestId: 123456, packetType: commandResponseType, payload: []byte("response")},
-				{requestId: 123456, packetType: commandResponseType, payload: []byte("Unknown request 64")},
+			responses: []protocol.Packet{
+				{RequestId: 123456, PacketType: protocol.CommandResponseType, Payload: []byte("response")},
+				{RequestId: 123456, PacketType: protocol.CommandResponseType, Payload: []byte("Unknown request 64")},
 			},
 			expected:  "response",
 			clientErr: nil,
@@ -247,11 +252,11 @@ func Test_rcon_Command(t *testing.T) {
 		{
 			name:    "positive case: fragment response",
 			command: "request",
-			responses: []packet{
-				{requestId: 123456, packetType: commandResponseType, payload: []byte(strings.Repeat("response", 4096/len("response")))},
-				{requestId: 123456, packetType: commandResponseType, payload: []byte(strings.Repeat("response", 4096/len("response")))},
-				{requestId: 123456, packetType: commandResponseType, payload: []byte(strings.Repeat("response", 1808/len("response")))},
-				{requestId: 123456, packetType: commandResponseType, payload: []byte("Unknown request 64")},
+			responses: []protocol.Packet{
+				{RequestId: 123456, PacketType: protocol.CommandResponseType, Payload: []byte(strings.Repeat("response", 4096/len("response")))},
+				{RequestId: 123456, PacketType: protocol.CommandResponseType, Payload: []byte(strings.Repeat("response", 4096/len("response")))},
+				{RequestId: 123456, PacketType: protocol.CommandResponseType, Payload: []byte(strings.Repeat("response", 1808/len("response")))},
+				{RequestId: 123456, PacketType: protocol.CommandResponseType, Payload: []byte("Unknown request 64")},
 			},
 			expected:  strings.Repeat("response", 10000/len("response")),
 			clientErr: nil,
@@ -270,30 +275,31 @@ func Test_rcon_Command(t *testing.T) {
 			go func() {
 				defer srv.Close()
 
-				req := new(packet)
-				if err := req.decode(srv); err != nil {
+				req := new(protocol.Packet)
+				if err := srv.dec.Decode(req); err != nil {
 					errCh <- err
 					return
 				}
 
 				res := tt.responses[0]
-				if err := res.encode(srv); err != nil {
+				if err := srv.enc.Encode(&res); err != nil {
 					errCh <- err
 				}
 
-				if res.length() < maxResponseLength {
+				if res.Length() < maxResponseLength {
 					errCh <- nil
 					return
 				}
 
-				dummy := new(packet)
-				if err := dummy.decode(srv); err != nil {
+				dummy := new(protocol.Packet)
+				if err := srv.dec.Decode(dummy); err != nil {
 					errCh <- err
 					return
 				}
 
 				for _, res := range tt.responses[1:] {
-					if err := res.encode(srv); err != nil {
+					res := res
+					if err := srv.enc.Encode(&res); err != nil {
 						errCh <- err
 					}
 				}
@@ -323,54 +329,58 @@ func Test_rcon_Command(t *testing.T) {
 
 func Test_rcon_request(t *testing.T) {
 	cases := []struct {
-		name      string
-		id        int32
-		typ       packetType
-		payload   []byte
-		responses []packet
-		expected  *packet
-		clientErr error
-		serverErr error
+		name          string
+		id            int32
+		typ           protocol.PacketType
+		payload       []byte
+		responses     []protocol.Packet
+		expected      *protocol.Packet
+		continuations int
+		clientErr     error
+		serverErr     error
 	}{
 		{
 			name:    "positive case: Auth Request",
 			id:      123456,
-			typ:     authRequestType,
+			typ:     protocol.AuthRequestType,
 			payload: []byte("minecraft"),
-			responses: []packet{
-				{requestId: 123456, packetType: authResponseType, payload: []byte{}},
+			responses: []protocol.Packet{
+				{RequestId: 123456, PacketType: protocol.AuthResponseType, Payload: []byte{}},
 			},
-			expected:  &packet{requestId: 123456, packetType: authResponseType, payload: []byte{}},
-			clientErr: nil,
-			serverErr: nil,
+			expected:      &protocol.Packet{RequestId: 123456, PacketType: protocol.AuthResponseType, Payload: []byte{}},
+			continuations: 0,
+			clientErr:     nil,
+			serverErr:     nil,
 		},
 		{
 			name:    "positive case: Command Request - non-fragment response",
 			id:      123456,
-			typ:     commandRequestType,
+			typ:     protocol.CommandRequestType,
 			payload: []byte("request"),
-			responses: []packet{
-				{requestId: 123456, packetType: commandResponseType, payload: []byte("response")},
-				{requestId: 123456, packetType: commandResponseType, payload: []byte("Unknown request 64")},
+			responses: []protocol.Packet{
+				{RequestId: 123456, PacketType: protocol.CommandResponseType, Payload: []byte("response")},
+				{RequestId: 123456, PacketType: protocol.CommandResponseType, Payload: []byte("Unknown request 64")},
 			},
-			expected:  &packet{requestId: 123456, packetType: commandResponseType, payload: []byte("response")},
-			clientErr: nil,
-			serverErr: nil,
+			expected:      &protocol.Packet{RequestId: 123456, PacketType: protocol.CommandResponseType, Payload: []byte("response")},
+			continuations: 0,
+			clientErr:     nil,
+			serverErr:     nil,
 		},
 		{
 			name:    "positive case: Command Request - fragment response",
 			id:      123456,
-			typ:     commandRequestType,
+			typ:     protocol.CommandRequestType,
 			payload: []byte("request"),
-			responses: []packet{
-				{requestId: 123456, packetType: commandResponseType, payload: []byte(strings.Repeat("response", maxResponsePayloadSize/len("response")))},
-				{requestId: 123456, packetType: commandResponseType, payload: []byte(strings.Repeat("response", maxResponsePayloadSize/len("response")))},
-				{requestId: 123456, packetType: commandResponseType, payload: []byte(strings.Repeat("response", (10000-maxResponsePayloadSize*2)/len("response")))},
-				{requestId: 123456, packetType: commandResponseType, payload: []byte("Unknown request 64")},
+			responses: []protocol.Packet{
+				{RequestId: 123456, PacketType: protocol.CommandResponseType, Payload: []byte(strings.Repeat("response", maxResponsePayloadSize/len("response")))},
+				{RequestId: 123456, PacketType: protocol.CommandResponseType, Payload: []byte(strings.Repeat("response", maxResponsePayloadSize/len("response")))},
+				{RequestId: 123456, PacketType: protocol.CommandResponseType, Payload: []byte(strings.Repeat("response", (10000-maxResponsePayloadSize*2)/len("response")))},
+				{RequestId: 123456, PacketType: protocol.CommandResponseType, Payload: []byte("Unknown request 64")},
 			},
-			expected:  &packet{requestId: 123456, packetType: commandResponseType, payload: []byte(strings.Repeat("response", 10000/len("response")))},
-			clientErr: nil,
-			serverErr: nil,
+			expected:      &protocol.Packet{RequestId: 123456, PacketType: protocol.CommandResponseType, Payload: []byte(strings.Repeat("response", 10000/len("response")))},
+			continuations: 2,
+			clientErr:     nil,
+			serverErr:     nil,
 		},
 	}
 
@@ -385,30 +395,31 @@ func Test_rcon_request(t *testing.T) {
 			go func() {
 				defer srv.Close()
 
-				req := new(packet)
-				if err := req.decode(srv); err != nil {
+				req := new(protocol.Packet)
+				if err := srv.dec.Decode(req); err != nil {
 					errCh <- err
 					return
 				}
 
 				res := tt.responses[0]
-				if err := res.encode(srv); err != nil {
+				if err := srv.enc.Encode(&res); err != nil {
 					errCh <- err
 				}
 
-				if res.length() < maxResponseLength {
+				if res.Length() < maxResponseLength {
 					errCh <- nil
 					return
 				}
 
-				dummy := new(packet)
-				if err := dummy.decode(srv); err != nil {
+				dummy := new(protocol.Packet)
+				if err := srv.dec.Decode(dummy); err != nil {
 					errCh <- err
 					return
 				}
 
 				for _, res := range tt.responses[1:] {
-					if err := res.encode(srv); err != nil {
+					res := res
+					if err := srv.enc.Encode(&res); err != nil {
 						errCh <- err
 					}
 				}
@@ -416,11 +427,12 @@ func Test_rcon_request(t *testing.T) {
 				errCh <- nil
 			}()
 
-			actual, cltErr := clt.request(tt.id, tt.typ, tt.payload)
+			actual, continuations, cltErr := clt.request(context.Background(), tt.id, tt.typ, tt.payload)
 
 			if tt.clientErr == nil {
 				assert.NoError(t, cltErr)
 				assert.Equal(t, tt.expected, actual)
+				assert.Equal(t, tt.continuations, continuations)
 			} else {
 				assert.Error(t, cltErr)
 				assert.Equal(t, tt.clientErr, cltErr)
@@ -435,3 +447,393 @@ func Test_rcon_request(t *testing.T) {
 		})
 	}
 }
+
+func Test_rcon_SendPacket(t *testing.T) {
+	cases := []struct {
+		name      string
+		packet    *protocol.Packet
+		clientErr error
+		serverErr error
+	}{
+		{
+			name:      "positive case: Dummy Request",
+			packet:    &protocol.Packet{RequestId: 123456, PacketType: protocol.DummyRequestType, Payload: []byte{}},
+			clientErr: nil,
+			serverErr: nil,
+		},
+		{
+			name:      "positive case: vendor extension",
+			packet:    &protocol.Packet{RequestId: 123456, PacketType: protocol.PacketType(42), Payload: []byte("keep-alive")},
+			clientErr: nil,
+			serverErr: nil,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, clt := pipe()
+			defer clt.Close()
+
+			errCh := make(chan error, 1)
+			defer close(errCh)
+
+			packetCh := make(chan *protocol.Packet, 1)
+			defer close(packetCh)
+
+			go func() {
+				defer srv.Close()
+
+				p, err := srv.ReadPacket()
+				errCh <- err
+				packetCh <- p
+			}()
+
+			cltErr := clt.SendPacket(tt.packet)
+
+			if tt.clientErr == nil {
+				assert.NoError(t, cltErr)
+			} else {
+				assert.Error(t, cltErr)
+				assert.IsType(t, tt.clientErr, cltErr)
+			}
+
+			srvErr := <-errCh
+			srvPacket := <-packetCh
+			if tt.serverErr == nil {
+				assert.NoError(t, srvErr)
+				assert.Equal(t, tt.packet, srvPacket)
+			} else {
+				assert.Error(t, srvErr)
+			}
+		})
+	}
+}
+
+func Test_rcon_ReadPacket(t *testing.T) {
+	cases := []struct {
+		name      string
+		raw       []byte
+		expected  *protocol.Packet
+		clientErr error
+	}{
+		{
+			name: "positive case",
+			raw: []byte{
+				0x0A, 0x00, 0x00, 0x00,
+				0x40, 0xE2, 0x01, 0x00,
+				0x2A, 0x00, 0x00, 0x00,
+				0x00,
+				0x00,
+			},
+			expected:  &protocol.Packet{RequestId: 123456, PacketType: protocol.PacketType(42), Payload: []byte{}},
+			clientErr: nil,
+		},
+		{
+			name:      "negative case: invalid length",
+			raw:       []byte{0x02, 0x00, 0x00, 0x00},
+			expected:  nil,
+			clientErr: &RCONError{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, clt := pipe()
+			defer clt.Close()
+
+			go func() {
+				defer srv.Close()
+
+				srv.Write(tt.raw)
+			}()
+
+			actual, cltErr := clt.ReadPacket()
+
+			if tt.clientErr == nil {
+				assert.NoError(t, cltErr)
+				assert.Equal(t, tt.expected, actual)
+			} else {
+				assert.Error(t, cltErr)
+				assert.IsType(t, tt.clientErr, cltErr)
+				assert.Nil(t, actual)
+			}
+		})
+	}
+}
+
+func Test_rcon_RoundTrip(t *testing.T) {
+	cases := []struct {
+		name      string
+		packet    *protocol.Packet
+		reply     *protocol.Packet
+		clientErr error
+	}{
+		{
+			name:   "positive case: vendor extension",
+			packet: &protocol.Packet{RequestId: 123456, PacketType: protocol.PacketType(42), Payload: []byte("keep-alive")},
+			reply:  &protocol.Packet{RequestId: 123456, PacketType: protocol.PacketType(43), Payload: []byte("alive")},
+		},
+		{
+			name:      "negative case: no reply",
+			packet:    &protocol.Packet{RequestId: 123456, PacketType: protocol.PacketType(42), Payload: []byte("keep-alive")},
+			reply:     nil,
+			clientErr: &RCONError{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, clt := pipe()
+			defer clt.Close()
+
+			errCh := make(chan error, 1)
+			go func() {
+				defer srv.Close()
+
+				p, err := srv.ReadPacket()
+				if err == nil && tt.reply != nil {
+					assert.Equal(t, tt.packet, p)
+					err = srv.SendPacket(tt.reply)
+				}
+				errCh <- err
+			}()
+
+			actual, cltErr := clt.RoundTrip(tt.packet)
+			assert.NoError(t, <-errCh)
+
+			if tt.clientErr == nil {
+				assert.NoError(t, cltErr)
+				assert.Equal(t, tt.reply, actual)
+			} else {
+				assert.Error(t, cltErr)
+				assert.IsType(t, tt.clientErr, cltErr)
+				assert.Nil(t, actual)
+			}
+		})
+	}
+}
+
+func Test_rcon_RoundTrip_concurrent(t *testing.T) {
+	srv, clt := pipe()
+	defer clt.Close()
+
+	go func() {
+		defer srv.Close()
+
+		for {
+			p, err := srv.ReadPacket()
+			if err != nil {
+				return
+			}
+
+			// NOTE: echo the payload with the type of the request
+			typ := protocol.CommandResponseType
+			if p.PacketType != protocol.CommandRequestType {
+				typ = p.PacketType
+			}
+			if err := srv.SendPacket(protocol.NewPacket(p.RequestId, typ, p.Payload)); err != nil {
+				return
+			}
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 50; i++ {
+			res, err := clt.Command("request")
+			assert.NoError(t, err)
+			assert.Equal(t, "request", res)
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		p := protocol.NewPacket(int32(i), protocol.PacketType(42), []byte("vendor"))
+		res, err := clt.RoundTrip(p)
+		assert.NoError(t, err)
+		assert.Equal(t, p, res)
+	}
+
+	<-done
+}
+
+func Test_rcon_Stats(t *testing.T) {
+	srv, clt := pipe()
+	defer clt.Close()
+
+	errCh := make(chan error, 1)
+	defer close(errCh)
+
+	go func() {
+		defer srv.Close()
+
+		req := new(protocol.Packet)
+		if err := srv.dec.Decode(req); err != nil {
+			errCh <- err
+			return
+		}
+
+		res := protocol.NewPacket(req.RequestId, protocol.CommandResponseType, []byte("response"))
+		if err := srv.enc.Encode(res); err != nil {
+			errCh <- err
+			return
+		}
+
+		errCh <- nil
+	}()
+
+	_, cltErr := clt.Command("request")
+	assert.NoError(t, cltErr)
+	assert.NoError(t, <-errCh)
+
+	_, cltErr = clt.Command("request")
+	assert.Error(t, cltErr)
+
+	expected := Stats{Commands: 1, Failures: 1, PacketsSent: 1, PacketsReceived: 1}
+	assert.Equal(t, expected, clt.Stats())
+}
+
+func Test_rcon_CommandContext(t *testing.T) {
+	t.Run("negative case: canceled", func(t *testing.T) {
+		srv, clt := pipe()
+		defer srv.Close()
+		defer clt.Close()
+
+		buf := new(bytes.Buffer)
+		ctx := liblog.NewContext(context.Background(), liblog.NewStdLogger(log.New(buf, "", 0), liblog.LevelInfo))
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := clt.CommandContext(ctx, "request")
+		assert.Error(t, err)
+		assert.IsType(t, &RCONError{}, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Contains(t, buf.String(), "failed to command")
+	})
+
+	t.Run("negative case: deadline exceeded", func(t *testing.T) {
+		srv, clt := pipe()
+		defer srv.Close()
+		defer clt.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), mockTimeout)
+		defer cancel()
+
+		// NOTE: server never responds
+		_, err := clt.CommandContext(ctx, "request")
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("negative case: interrupted exchange breaks the connection", func(t *testing.T) {
+		srv, clt := pipe()
+		defer srv.Close()
+		defer clt.Close()
+
+		go func() {
+			req, err := srv.ReadPacket()
+			if err != nil {
+				return
+			}
+
+			// NOTE: respond after the client gave up
+			time.Sleep(5 * mockTimeout)
+			_ = srv.SendPacket(protocol.NewPacket(req.RequestId, protocol.CommandResponseType, []byte("late")))
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), mockTimeout)
+		defer cancel()
+
+		_, err := clt.CommandContext(ctx, "request")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		_, err = clt.Command("request")
+		assert.IsType(t, &RCONError{}, err)
+		assert.ErrorIs(t, err, ErrBroken)
+
+		assert.NoError(t, clt.Close())
+	})
+}
+
+func Test_rcon_watch(t *testing.T) {
+	t.Run("positive case: finished before interruption", func(t *testing.T) {
+		srv, clt := pipe()
+		defer srv.Close()
+		defer clt.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stop := clt.watch(ctx)
+		stop(nil)
+		cancel()
+
+		assert.False(t, clt.broken.Load())
+	})
+
+	t.Run("positive case: interrupted after the I/O succeeded", func(t *testing.T) {
+		srv, clt := pipe()
+		defer srv.Close()
+		defer clt.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stop := clt.watch(ctx)
+		cancel()
+		time.Sleep(mockTimeout)
+		stop(nil)
+
+		assert.False(t, clt.broken.Load())
+
+		// NOTE: the deadline set by the interruption is cleared
+		go func() {
+			req, err := srv.ReadPacket()
+			if err != nil {
+				return
+			}
+
+			_ = srv.SendPacket(protocol.NewPacket(req.RequestId, protocol.CommandResponseType, []byte("response")))
+		}()
+
+		res, err := clt.Command("request")
+		assert.NoError(t, err)
+		assert.Equal(t, "response", res)
+	})
+
+	t.Run("positive case: restores the deadline of the caller", func(t *testing.T) {
+		srv, clt := pipe()
+		defer srv.Close()
+		defer clt.Close()
+
+		assert.NoError(t, clt.SetReadDeadline(time.Now().Add(3*mockTimeout)))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stop := clt.watch(ctx)
+		cancel()
+		time.Sleep(mockTimeout / 2)
+		stop(nil)
+
+		// NOTE: server never responds
+		start := time.Now()
+		_, err := clt.ReadPacket()
+		assert.Error(t, err)
+		assert.False(t, clt.broken.Load())
+		assert.GreaterOrEqual(t, time.Since(start), mockTimeout)
+		assert.Less(t, time.Since(start), 5*mockTimeout)
+	})
+
+	t.Run("negative case: interrupted I/O failed", func(t *testing.T) {
+		srv, clt := pipe()
+		defer srv.Close()
+		defer clt.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stop := clt.watch(ctx)
+		cancel()
+		time.Sleep(mockTimeout)
+		stop(context.Canceled)
+
+		assert.True(t, clt.broken.Load())
+
+		_, err := clt.ReadPacket()
+		assert.ErrorIs(t, err, ErrBroken)
+	})
+}
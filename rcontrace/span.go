@@ -0,0 +1,146 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package rcontrace
+
+import (
+	"sync"
+)
+
+// Tracer starts spans. Adapt it to a tracing library such as OpenTelemetry.
+type Tracer interface {
+	StartSpan(name string) Span
+}
+
+// Span is a timed operation started by a Tracer.
+type Span interface {
+	SetAttribute(key string, value any)
+	AddEvent(name string, attrs map[string]any)
+	End(err error)
+}
+
+// NewSpanTrace returns a ClientTrace that records a span for the DNS lookup,
+// the connect, the auth and the command, and an event for each packet.
+//
+// The spans are tracked per trace, so use a new trace for each dial or
+// command.
+func NewSpanTrace(t Tracer) *ClientTrace {
+	s := &spanTrace{tracer: t, spans: make(map[string]Span)}
+
+	return &ClientTrace{
+		DNSStart: func(info DNSStartInfo) {
+			s.start("rcon.dns").SetAttribute("host", info.Host)
+		},
+		DNSDone: func(info DNSDoneInfo) {
+			s.end("rcon.dns", info.Err)
+		},
+		ConnectStart: func(network string, addr string) {
+			span := s.start("rcon.connect")
+			span.SetAttribute("network", network)
+			span.SetAttribute("addr", addr)
+		},
+		ConnectDone: func(network string, addr string, err error) {
+			s.end("rcon.connect", err)
+		},
+		AuthSent: func() {
+			s.start("rcon.auth")
+		},
+		AuthDone: func(err error) {
+			s.end("rcon.auth", err)
+		},
+		PacketWritten: func(info PacketInfo) {
+			s.event("packet.written", info)
+		},
+		PacketRead: func(info PacketInfo) {
+			s.event("packet.read", info)
+		},
+		CommandStart: func(info CommandStartInfo) {
+			s.start("rcon.command").SetAttribute("command", info.Command)
+		},
+		CommandDone: func(info CommandDoneInfo) {
+			s.mu.Lock()
+			span, ok := s.spans["rcon.command"]
+			s.mu.Unlock()
+
+			if ok {
+				span.SetAttribute("size", info.Size)
+				span.SetAttribute("continuations", info.Continuations)
+			}
+
+			s.end("rcon.command", info.Err)
+		},
+	}
+}
+
+type spanTrace struct {
+	tracer Tracer
+
+	mu    sync.Mutex
+	spans map[string]Span
+}
+
+func (s *spanTrace) start(name string) Span {
+	span := s.tracer.StartSpan(name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.spans[name] = span
+
+	return span
+}
+
+func (s *spanTrace) end(name string, err error) {
+	s.mu.Lock()
+	span, ok := s.spans[name]
+	delete(s.spans, name)
+	s.mu.Unlock()
+
+	if ok {
+		span.End(err)
+	}
+}
+
+// event adds a packet event to the innermost open span.
+func (s *spanTrace) event(name string, info PacketInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, n := range []string{"rcon.command", "rcon.auth"} {
+		span, ok := s.spans[n]
+		if !ok {
+			continue
+		}
+
+		attrs := map[string]any{
+			"id":           info.RequestId,
+			"type":         int32(info.PacketType),
+			"length":       info.Length,
+			"continuation": info.Continuation,
+		}
+		if info.Err != nil {
+			attrs["error"] = info.Err
+		}
+
+		span.AddEvent(name, attrs)
+
+		return
+	}
+}
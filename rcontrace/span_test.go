@@ -0,0 +1,97 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package rcontrace
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Aton-Kish/gorcon/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTracer struct {
+	log []string
+}
+
+func (m *mockTracer) StartSpan(name string) Span {
+	m.log = append(m.log, "start "+name)
+	return &mockSpan{name: name, tracer: m}
+}
+
+type mockSpan struct {
+	name   string
+	tracer *mockTracer
+}
+
+func (s *mockSpan) SetAttribute(key string, value any) {
+	s.tracer.log = append(s.tracer.log, fmt.Sprintf("%s %s=%v", s.name, key, value))
+}
+
+func (s *mockSpan) AddEvent(name string, attrs map[string]any) {
+	s.tracer.log = append(s.tracer.log, fmt.Sprintf("%s event %s type=%v continuation=%v", s.name, name, attrs["type"], attrs["continuation"]))
+}
+
+func (s *mockSpan) End(err error) {
+	s.tracer.log = append(s.tracer.log, fmt.Sprintf("end %s %v", s.name, err))
+}
+
+func TestNewSpanTrace(t *testing.T) {
+	tracer := new(mockTracer)
+	trace := NewSpanTrace(tracer)
+
+	trace.DNSStart(DNSStartInfo{Host: "localhost"})
+	trace.DNSDone(DNSDoneInfo{})
+	trace.ConnectStart("tcp", "127.0.0.1:25575")
+	trace.ConnectDone("tcp", "127.0.0.1:25575", nil)
+	trace.PacketWritten(PacketInfo{PacketType: protocol.AuthRequestType})
+	trace.AuthSent()
+	trace.PacketRead(PacketInfo{PacketType: protocol.AuthResponseType})
+	trace.AuthDone(errors.New("unauthorized"))
+	trace.CommandStart(CommandStartInfo{Command: "/seed"})
+	trace.PacketWritten(PacketInfo{PacketType: protocol.CommandRequestType})
+	trace.PacketRead(PacketInfo{PacketType: protocol.CommandResponseType, Continuation: true})
+	trace.CommandDone(CommandDoneInfo{Command: "/seed", Size: 8, Continuations: 1})
+
+	expected := []string{
+		"start rcon.dns",
+		"rcon.dns host=localhost",
+		"end rcon.dns <nil>",
+		"start rcon.connect",
+		"rcon.connect network=tcp",
+		"rcon.connect addr=127.0.0.1:25575",
+		"end rcon.connect <nil>",
+		"start rcon.auth",
+		"rcon.auth event packet.read type=2 continuation=false",
+		"end rcon.auth unauthorized",
+		"start rcon.command",
+		"rcon.command command=/seed",
+		"rcon.command event packet.written type=2 continuation=false",
+		"rcon.command event packet.read type=0 continuation=true",
+		"rcon.command size=8",
+		"rcon.command continuations=1",
+		"end rcon.command <nil>",
+	}
+	assert.Equal(t, expected, tracer.log)
+}
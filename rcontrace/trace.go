@@ -0,0 +1,115 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package rcontrace provides hooks to trace events within RCON clients,
+// modelled on net/http/httptrace.
+package rcontrace
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/Aton-Kish/gorcon/protocol"
+)
+
+// ClientTrace is a set of hooks run at various stages of a dial or a command.
+// Any particular hook may be nil. Hooks may be called concurrently from
+// different goroutines.
+type ClientTrace struct {
+	// DNSStart is called when a DNS lookup begins. It is only called when the
+	// address is a host name.
+	DNSStart func(DNSStartInfo)
+	// DNSDone is called when a DNS lookup ends.
+	DNSDone func(DNSDoneInfo)
+
+	// ConnectStart is called when a new connection's dial begins. It may be
+	// called once per resolved address.
+	ConnectStart func(network string, addr string)
+	// ConnectDone is called when a new connection's dial completes.
+	ConnectDone func(network string, addr string, err error)
+
+	// AuthSent is called after the auth packet is written.
+	AuthSent func()
+	// AuthDone is called when the auth response is read. err is nil if the
+	// password was accepted.
+	AuthDone func(err error)
+
+	// PacketWritten is called after each packet is written.
+	PacketWritten func(PacketInfo)
+	// PacketRead is called after each packet is read, including the
+	// continuation packets of a split response.
+	PacketRead func(PacketInfo)
+
+	// CommandStart is called before a command is sent.
+	CommandStart func(CommandStartInfo)
+	// CommandDone is called when a command completes.
+	CommandDone func(CommandDoneInfo)
+}
+
+type DNSStartInfo struct {
+	Host string
+}
+
+type DNSDoneInfo struct {
+	Addrs []net.IPAddr
+	Err   error
+}
+
+type PacketInfo struct {
+	RequestId  int32
+	PacketType protocol.PacketType
+	Length     int
+	// Continuation reports whether the packet continues a split response.
+	Continuation bool
+	Err          error
+}
+
+type CommandStartInfo struct {
+	// Command is the command after redaction.
+	Command string
+}
+
+type CommandDoneInfo struct {
+	// Command is the command after redaction.
+	Command       string
+	Duration      time.Duration
+	Size          int
+	Continuations int
+	Err           error
+}
+
+type contextKey struct{}
+
+// WithClientTrace returns a new context based on ctx. Dials and commands made
+// with the returned context use the provided trace hooks.
+func WithClientTrace(ctx context.Context, trace *ClientTrace) context.Context {
+	if trace == nil {
+		panic("nil trace")
+	}
+
+	return context.WithValue(ctx, contextKey{}, trace)
+}
+
+// ContextClientTrace returns the ClientTrace associated with ctx, or nil.
+func ContextClientTrace(ctx context.Context) *ClientTrace {
+	trace, _ := ctx.Value(contextKey{}).(*ClientTrace)
+	return trace
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package rcon
+
+import (
+	"strings"
+
+	"github.com/Aton-Kish/gorcon/protocol"
+)
+
+// Redactor rewrites a command before it is logged, hiding sensitive arguments.
+type Redactor func(command string) string
+
+// RedactArgs returns a Redactor that hides the arguments of the given commands.
+// Names are matched with or without the leading slash, so both "/tellraw @a
+// {...}" and "tellraw @a {...}" are logged as "tellraw <redacted>" for "tellraw".
+func RedactArgs(commands ...string) Redactor {
+	names := make(map[string]struct{}, len(commands))
+	for _, c := range commands {
+		names[strings.TrimPrefix(c, "/")] = struct{}{}
+	}
+
+	return func(command string) string {
+		name, args, ok := strings.Cut(command, " ")
+		if !ok || strings.TrimSpace(args) == "" {
+			return command
+		}
+
+		if _, ok := names[strings.TrimPrefix(name, "/")]; !ok {
+			return command
+		}
+
+		return name + " " + protocol.Redacted
+	}
+}
+
+func (c *rcon) redact(command string) string {
+	if c.redactor == nil {
+		return command
+	}
+
+	return c.redactor(command)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package rcon
+
+import (
+	"bytes"
+	"log"
+	"net"
+	"testing"
+
+	liblog "github.com/Aton-Kish/gorcon/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactArgs(t *testing.T) {
+	redact := RedactArgs("/whitelist", "tellraw")
+
+	cases := []struct {
+		name     string
+		command  string
+		expected string
+	}{
+		{
+			name:     "positive case: slash",
+			command:  "/whitelist add jeb_",
+			expected: "/whitelist <redacted>",
+		},
+		{
+			name:     "positive case: without slash",
+			command:  `tellraw @a {"text":"secret"}`,
+			expected: "tellraw <redacted>",
+		},
+		{
+			name:     "positive case: other command",
+			command:  "/give jeb_ minecraft:dirt 1",
+			expected: "/give jeb_ minecraft:dirt 1",
+		},
+		{
+			name:     "positive case: no arguments",
+			command:  "/whitelist",
+			expected: "/whitelist",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, redact(tt.command))
+		})
+	}
+}
+
+func TestWithRedactor(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	srv, conn := net.Pipe()
+	srv.Close()
+
+	clt := newRCON(
+		conn,
+		WithLeveledLogger(liblog.NewStdLogger(log.New(buf, "", 0), liblog.LevelDebug)),
+		WithRedactor(RedactArgs("/whitelist")),
+	)
+	defer clt.Close()
+
+	_, err := clt.Command("/whitelist add jeb_")
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), `command="/whitelist <redacted>"`)
+	assert.NotContains(t, buf.String(), "jeb_")
+}
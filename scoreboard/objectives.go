@@ -0,0 +1,118 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package scoreboard
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+)
+
+// Criteria is the criteria of an objective.
+type Criteria string
+
+const (
+	Dummy           Criteria = "dummy"
+	Trigger         Criteria = "trigger"
+	DeathCount      Criteria = "deathCount"
+	PlayerKillCount Criteria = "playerKillCount"
+	TotalKillCount  Criteria = "totalKillCount"
+	Health          Criteria = "health"
+	XP              Criteria = "xp"
+	Level           Criteria = "level"
+	Food            Criteria = "food"
+	Air             Criteria = "air"
+	Armor           Criteria = "armor"
+)
+
+// DisplaySlot is where an objective is displayed.
+type DisplaySlot string
+
+const (
+	List      DisplaySlot = "list"
+	Sidebar   DisplaySlot = "sidebar"
+	BelowName DisplaySlot = "below_name"
+)
+
+// SidebarTeam returns the sidebar slot shown to members of teams of color.
+func SidebarTeam(color minecraft.Color) DisplaySlot {
+	return DisplaySlot("sidebar.team." + string(color))
+}
+
+var objectivesRe = regexp.MustCompile(`^There are (\d+) objectives?(?:\(s\))?: (.*)$`)
+
+// AddObjective creates an objective. A nil display name defaults to the
+// name.
+func (s *Scoreboard) AddObjective(ctx context.Context, name string, criteria Criteria, displayName *minecraft.Text) error {
+	command := "/scoreboard objectives add " + name + " " + string(criteria)
+	if displayName != nil {
+		command += " " + displayName.String()
+	}
+
+	_, err := s.run(ctx, "objectives add", command, "Created new objective")
+	return err
+}
+
+// RemoveObjective removes an objective and its scores.
+func (s *Scoreboard) RemoveObjective(ctx context.Context, name string) error {
+	_, err := s.run(ctx, "objectives remove", "/scoreboard objectives remove "+name, "Removed objective")
+	return err
+}
+
+// Objectives lists the display names of the objectives; the server does not
+// list their names.
+func (s *Scoreboard) Objectives(ctx context.Context) ([]string, error) {
+	res, err := s.run(ctx, "objectives list", "/scoreboard objectives list", "There are")
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(res, "There are no objectives") {
+		return []string{}, nil
+	}
+
+	g := objectivesRe.FindStringSubmatch(res)
+	if g == nil {
+		return nil, &minecraft.UnexpectedOutputError{Command: "scoreboard objectives list", Output: res}
+	}
+
+	return splitList(g[2]), nil
+}
+
+// SetDisplayName renames an objective.
+func (s *Scoreboard) SetDisplayName(ctx context.Context, objective string, displayName minecraft.Text) error {
+	_, err := s.run(ctx, "objectives modify", "/scoreboard objectives modify "+objective+" displayname "+displayName.String(), "Changed the display name")
+	return err
+}
+
+// SetDisplay shows an objective in a display slot. An empty objective clears
+// the slot.
+func (s *Scoreboard) SetDisplay(ctx context.Context, slot DisplaySlot, objective string) error {
+	command := "/scoreboard objectives setdisplay " + string(slot)
+	if objective != "" {
+		command += " " + objective
+	}
+
+	_, err := s.run(ctx, "objectives setdisplay", command, "Set display slot", "Cleared any objectives")
+	return err
+}
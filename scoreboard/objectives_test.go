@@ -0,0 +1,169 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package scoreboard
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSidebarTeam(t *testing.T) {
+	assert.Equal(t, DisplaySlot("sidebar.team.red"), SidebarTeam(minecraft.Red))
+}
+
+func TestScoreboard_objectives(t *testing.T) {
+	kills := minecraft.NewText("Kills").WithColor(minecraft.Red)
+
+	cases := []struct {
+		name     string
+		run      func(s *Scoreboard) error
+		command  string
+		response string
+		err      error
+	}{
+		{
+			name:     "positive case: add",
+			run:      func(s *Scoreboard) error { return s.AddObjective(context.Background(), "kills", PlayerKillCount, nil) },
+			command:  "/scoreboard objectives add kills playerKillCount",
+			response: "Created new objective [kills]",
+		},
+		{
+			name: "positive case: add with display name",
+			run: func(s *Scoreboard) error {
+				return s.AddObjective(context.Background(), "kills", PlayerKillCount, &kills)
+			},
+			command:  `/scoreboard objectives add kills playerKillCount {"text":"Kills","color":"red"}`,
+			response: "Created new objective [Kills]",
+		},
+		{
+			name:     "negative case: add existing",
+			run:      func(s *Scoreboard) error { return s.AddObjective(context.Background(), "kills", Dummy, nil) },
+			command:  "/scoreboard objectives add kills dummy",
+			response: "An objective already exists by that name",
+			err:      ErrObjectiveExists,
+		},
+		{
+			name:     "positive case: remove",
+			run:      func(s *Scoreboard) error { return s.RemoveObjective(context.Background(), "kills") },
+			command:  "/scoreboard objectives remove kills",
+			response: "Removed objective [kills]",
+		},
+		{
+			name:     "negative case: remove unknown",
+			run:      func(s *Scoreboard) error { return s.RemoveObjective(context.Background(), "kills") },
+			command:  "/scoreboard objectives remove kills",
+			response: "Unknown scoreboard objective 'kills'",
+			err:      ErrUnknownObjective,
+		},
+		{
+			name:     "positive case: set display name",
+			run:      func(s *Scoreboard) error { return s.SetDisplayName(context.Background(), "kills", kills) },
+			command:  `/scoreboard objectives modify kills displayname {"text":"Kills","color":"red"}`,
+			response: "Changed the display name of [kills] to [Kills]",
+		},
+		{
+			name:     "positive case: set display",
+			run:      func(s *Scoreboard) error { return s.SetDisplay(context.Background(), Sidebar, "kills") },
+			command:  "/scoreboard objectives setdisplay sidebar kills",
+			response: "Set display slot sidebar to show objective [kills]",
+		},
+		{
+			name:     "positive case: set display unchanged",
+			run:      func(s *Scoreboard) error { return s.SetDisplay(context.Background(), Sidebar, "kills") },
+			command:  "/scoreboard objectives setdisplay sidebar kills",
+			response: "Nothing changed. That display slot is already showing that objective",
+		},
+		{
+			name:     "positive case: clear display",
+			run:      func(s *Scoreboard) error { return s.SetDisplay(context.Background(), BelowName, "") },
+			command:  "/scoreboard objectives setdisplay below_name",
+			response: "Cleared any objectives in display slot below_name",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockCommander{responses: map[string]string{tt.command: tt.response}}
+			s := New(m)
+
+			err := tt.run(s)
+			assert.Equal(t, []string{tt.command}, m.commands)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestScoreboard_Objectives(t *testing.T) {
+	cases := []struct {
+		name     string
+		response string
+		expected []string
+		err      error
+	}{
+		{
+			name:     "positive case",
+			response: "There are 2 objective(s): [Kills], [Deaths]",
+			expected: []string{"Kills", "Deaths"},
+		},
+		{
+			name:     "positive case: none",
+			response: "There are no objectives",
+			expected: []string{},
+		},
+		{
+			name:     "negative case: unexpected output",
+			response: "There are many objectives",
+			err:      &minecraft.UnexpectedOutputError{Command: "scoreboard objectives list", Output: "There are many objectives"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockCommander{responses: map[string]string{"/scoreboard objectives list": tt.response}}
+			s := New(m)
+
+			actual, err := s.Objectives(context.Background())
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, actual)
+			} else {
+				assert.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
+
+func TestScoreboard_Objectives_commandError(t *testing.T) {
+	m := &mockCommander{responses: map[string]string{}}
+	s := New(m)
+
+	_, err := s.Objectives(context.Background())
+	assert.EqualError(t, err, "unknown command")
+}
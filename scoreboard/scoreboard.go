@@ -0,0 +1,147 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package scoreboard manages objectives, scores and teams through RCON.
+//
+// Operations that change nothing, such as setting a display slot to the
+// objective it already shows, succeed. Known failures are reported as a
+// CommandError wrapping one of the sentinel errors.
+package scoreboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+)
+
+var (
+	ErrObjectiveExists  = errors.New("objective already exists")
+	ErrUnknownObjective = errors.New("unknown objective")
+	ErrTeamExists       = errors.New("team already exists")
+	ErrUnknownTeam      = errors.New("unknown team")
+	ErrNoScore          = errors.New("no score is set")
+	ErrNotFound         = errors.New("no entity was found")
+	ErrNotTrigger       = errors.New("objective is not a trigger")
+)
+
+// CommandError is returned when the server rejects a command.
+type CommandError struct {
+	Op       string
+	Response string
+	Err      error
+}
+
+func (e *CommandError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	var err string
+	if e.Err == nil {
+		err = "<nil>"
+	} else {
+		err = e.Err.Error()
+	}
+
+	return fmt.Sprintf("scoreboard %s: %s", e.Op, err)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// failures maps the prefixes of vanilla error responses to errors.
+var failures = []struct {
+	prefix string
+	err    error
+}{
+	{"An objective already exists by that name", ErrObjectiveExists},
+	{"Unknown scoreboard objective", ErrUnknownObjective},
+	{"A team already exists by that name", ErrTeamExists},
+	{"Unknown team", ErrUnknownTeam},
+	{"Can't get value of", ErrNoScore},
+	{"No entity was found", ErrNotFound},
+	{"No player was found", ErrNotFound},
+	{"That player does not exist", ErrNotFound},
+	{"Only trigger objectives can be enabled", ErrNotTrigger},
+}
+
+// Scoreboard runs scoreboard commands through a connection.
+type Scoreboard struct {
+	c minecraft.Commander
+}
+
+// New returns a Scoreboard using c, such as an rcon.RCON or an rcon.Client.
+func New(c minecraft.Commander) *Scoreboard {
+	return &Scoreboard{c: c}
+}
+
+// run runs the command and returns the response if it starts with one of the
+// success prefixes or reports that nothing changed.
+func (s *Scoreboard) run(ctx context.Context, op, command string, success ...string) (string, error) {
+	res, err := minecraft.Run(ctx, s.c, minecraft.Cmd(command))
+	if err != nil {
+		return "", err
+	}
+
+	if strings.HasPrefix(res, "Nothing changed") {
+		return res, nil
+	}
+
+	for _, prefix := range success {
+		if strings.HasPrefix(res, prefix) {
+			return res, nil
+		}
+	}
+
+	return "", failure(op, res)
+}
+
+// failure returns the error of a response.
+func failure(op, res string) error {
+	for _, f := range failures {
+		if strings.HasPrefix(res, f.prefix) {
+			return &CommandError{Op: op, Response: res, Err: f.err}
+		}
+	}
+
+	return &minecraft.UnexpectedOutputError{Command: "scoreboard " + op, Output: res}
+}
+
+// splitList splits the names of a list response, removing the brackets around
+// display names.
+func splitList(s string) []string {
+	names := []string{}
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if strings.HasPrefix(name, "[") && strings.HasSuffix(name, "]") {
+			name = name[1 : len(name)-1]
+		}
+
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package scoreboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCommander answers commands from responses.
+type mockCommander struct {
+	commands  []string
+	responses map[string]string
+}
+
+func (m *mockCommander) Command(command string) (string, error) {
+	return m.CommandContext(context.Background(), command)
+}
+
+func (m *mockCommander) CommandContext(ctx context.Context, command string) (string, error) {
+	m.commands = append(m.commands, command)
+
+	res, ok := m.responses[command]
+	if !ok {
+		return "", errors.New("unknown command")
+	}
+
+	return res, nil
+}
+
+func TestCommandError_Error(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      *CommandError
+		expected string
+	}{
+		{
+			name:     "positive case",
+			err:      &CommandError{Op: "team add", Response: "A team already exists by that name", Err: ErrTeamExists},
+			expected: "scoreboard team add: team already exists",
+		},
+		{
+			name:     "positive case: nil error",
+			err:      &CommandError{Op: "team add"},
+			expected: "scoreboard team add: <nil>",
+		},
+		{
+			name:     "positive case: nil",
+			err:      nil,
+			expected: "<nil>",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.err.Error())
+		})
+	}
+}
+
+func TestScoreboard_run(t *testing.T) {
+	cases := []struct {
+		name     string
+		response string
+		expected string
+		err      error
+	}{
+		{
+			name:     "positive case: success",
+			response: "Created team [red]",
+			expected: "Created team [red]",
+		},
+		{
+			name:     "positive case: nothing changed",
+			response: "Nothing changed. That team already has that color",
+			expected: "Nothing changed. That team already has that color",
+		},
+		{
+			name:     "negative case: known failure",
+			response: "A team already exists by that name",
+			err:      &CommandError{Op: "team add", Response: "A team already exists by that name", Err: ErrTeamExists},
+		},
+		{
+			name:     "negative case: unexpected output",
+			response: "Unknown or incomplete command, see below for error",
+			err:      &minecraft.UnexpectedOutputError{Command: "scoreboard team add", Output: "Unknown or incomplete command, see below for error"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockCommander{responses: map[string]string{"/team add red": tt.response}}
+			s := New(m)
+
+			actual, err := s.run(context.Background(), "team add", "/team add red", "Created team")
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, actual)
+			} else {
+				assert.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
+
+func TestScoreboard_run_commandError(t *testing.T) {
+	m := &mockCommander{responses: map[string]string{}}
+	s := New(m)
+
+	_, err := s.run(context.Background(), "team add", "/team add red", "Created team")
+	assert.EqualError(t, err, "unknown command")
+}
+
+func TestSplitList(t *testing.T) {
+	cases := []struct {
+		name     string
+		s        string
+		expected []string
+	}{
+		{
+			name:     "positive case: display names",
+			s:        "[Kills], [Deaths]",
+			expected: []string{"Kills", "Deaths"},
+		},
+		{
+			name:     "positive case: names",
+			s:        "jeb_, Dinnerbone",
+			expected: []string{"jeb_", "Dinnerbone"},
+		},
+		{
+			name:     "positive case: empty",
+			s:        "",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, splitList(tt.s))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package scoreboard
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+)
+
+var (
+	trackedRe = regexp.MustCompile(`^There are (\d+) tracked entit(?:y|ies)(?:/entities)?: (.*)$`)
+	scoresRe  = regexp.MustCompile(`^\S+ has (\d+) scores?(?:\(s\))?:`)
+	entryRe   = regexp.MustCompile(`\[([^\]]*)\]: (-?\d+)`)
+)
+
+// Get gets the score of a target, which may be a selector matching one
+// entity.
+func (s *Scoreboard) Get(ctx context.Context, target, objective string) (int, error) {
+	score, err := s.score(ctx, target, objective)
+	if err != nil {
+		return 0, err
+	}
+
+	return score.Value, nil
+}
+
+// score gets the score of a target along with its resolved name.
+func (s *Scoreboard) score(ctx context.Context, target, objective string) (*minecraft.Score, error) {
+	res, err := minecraft.Run(ctx, s.c, minecraft.Cmd("/scoreboard players get "+target+" "+objective))
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := minecraft.ParseScore(res)
+	if err != nil {
+		return nil, failure("players get", res)
+	}
+
+	return score, nil
+}
+
+// Set sets the score of the targets.
+func (s *Scoreboard) Set(ctx context.Context, targets, objective string, value int) error {
+	_, err := s.run(ctx, "players set", "/scoreboard players set "+targets+" "+objective+" "+strconv.Itoa(value), "Set ")
+	return err
+}
+
+// Add adds delta, which may be negative, to the score of the targets.
+func (s *Scoreboard) Add(ctx context.Context, targets, objective string, delta int) error {
+	if delta < 0 {
+		_, err := s.run(ctx, "players remove", "/scoreboard players remove "+targets+" "+objective+" "+strconv.Itoa(-delta), "Removed ")
+		return err
+	}
+
+	_, err := s.run(ctx, "players add", "/scoreboard players add "+targets+" "+objective+" "+strconv.Itoa(delta), "Added ")
+	return err
+}
+
+// Reset resets the score of the targets. An empty objective resets all their
+// scores.
+func (s *Scoreboard) Reset(ctx context.Context, targets, objective string) error {
+	command := "/scoreboard players reset " + targets
+	if objective != "" {
+		command += " " + objective
+	}
+
+	_, err := s.run(ctx, "players reset", command, "Reset ")
+	return err
+}
+
+// Enable enables a trigger objective for the targets.
+func (s *Scoreboard) Enable(ctx context.Context, targets, objective string) error {
+	_, err := s.run(ctx, "players enable", "/scoreboard players enable "+targets+" "+objective, "Enabled trigger")
+	return err
+}
+
+// Tracked lists the entities with scores.
+func (s *Scoreboard) Tracked(ctx context.Context) ([]string, error) {
+	res, err := s.run(ctx, "players list", "/scoreboard players list", "There are")
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(res, "There are no tracked entities") {
+		return []string{}, nil
+	}
+
+	g := trackedRe.FindStringSubmatch(res)
+	if g == nil {
+		return nil, &minecraft.UnexpectedOutputError{Command: "scoreboard players list", Output: res}
+	}
+
+	return splitList(g[2]), nil
+}
+
+// Scores gets the scores of a target by objective display name.
+//
+// NOTE: the server may join the lines of the output without a separator, so
+// entries are matched as `[name]: value`.
+func (s *Scoreboard) Scores(ctx context.Context, target string) (map[string]int, error) {
+	res, err := s.run(ctx, "players list", "/scoreboard players list "+target, target+" has ")
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(res, target+" has no scores") {
+		return map[string]int{}, nil
+	}
+
+	h := scoresRe.FindStringSubmatch(res)
+	if h == nil {
+		return nil, &minecraft.UnexpectedOutputError{Command: "scoreboard players list", Output: res}
+	}
+
+	scores := map[string]int{}
+	for _, g := range entryRe.FindAllStringSubmatch(res[len(h[0]):], -1) {
+		v, err := strconv.Atoi(g[2])
+		if err != nil {
+			return nil, &minecraft.UnexpectedOutputError{Command: "scoreboard players list", Output: res}
+		}
+		scores[g[1]] = v
+	}
+
+	if n, _ := strconv.Atoi(h[1]); n != len(scores) {
+		return nil, &minecraft.UnexpectedOutputError{Command: "scoreboard players list", Output: res}
+	}
+
+	return scores, nil
+}
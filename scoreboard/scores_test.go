@@ -0,0 +1,242 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package scoreboard
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreboard_Get(t *testing.T) {
+	cases := []struct {
+		name     string
+		response string
+		expected int
+		err      error
+	}{
+		{
+			name:     "positive case",
+			response: "jeb_ has 12 [kills]",
+			expected: 12,
+		},
+		{
+			name:     "positive case: negative value",
+			response: "jeb_ has -3 [kills]",
+			expected: -3,
+		},
+		{
+			name:     "negative case: no score",
+			response: "Can't get value of kills for jeb_; none is set",
+			err:      ErrNoScore,
+		},
+		{
+			name:     "negative case: unknown objective",
+			response: "Unknown scoreboard objective 'kills'",
+			err:      ErrUnknownObjective,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockCommander{responses: map[string]string{"/scoreboard players get jeb_ kills": tt.response}}
+			s := New(m)
+
+			actual, err := s.Get(context.Background(), "jeb_", "kills")
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, actual)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestScoreboard_players(t *testing.T) {
+	cases := []struct {
+		name     string
+		run      func(s *Scoreboard) error
+		command  string
+		response string
+		err      error
+	}{
+		{
+			name:     "positive case: set",
+			run:      func(s *Scoreboard) error { return s.Set(context.Background(), "jeb_", "kills", 5) },
+			command:  "/scoreboard players set jeb_ kills 5",
+			response: "Set [kills] for jeb_ to 5",
+		},
+		{
+			name:     "negative case: set unknown player",
+			run:      func(s *Scoreboard) error { return s.Set(context.Background(), "@p[name=nobody_]", "kills", 5) },
+			command:  "/scoreboard players set @p[name=nobody_] kills 5",
+			response: "No player was found",
+			err:      ErrNotFound,
+		},
+		{
+			name:     "positive case: add",
+			run:      func(s *Scoreboard) error { return s.Add(context.Background(), "jeb_", "kills", 2) },
+			command:  "/scoreboard players add jeb_ kills 2",
+			response: "Added 2 to [kills] for jeb_ (now 7)",
+		},
+		{
+			name:     "positive case: add negative",
+			run:      func(s *Scoreboard) error { return s.Add(context.Background(), "jeb_", "kills", -2) },
+			command:  "/scoreboard players remove jeb_ kills 2",
+			response: "Removed 2 from [kills] for jeb_ (now 3)",
+		},
+		{
+			name:     "positive case: reset",
+			run:      func(s *Scoreboard) error { return s.Reset(context.Background(), "jeb_", "kills") },
+			command:  "/scoreboard players reset jeb_ kills",
+			response: "Reset [kills] for jeb_",
+		},
+		{
+			name:     "positive case: reset all",
+			run:      func(s *Scoreboard) error { return s.Reset(context.Background(), "jeb_", "") },
+			command:  "/scoreboard players reset jeb_",
+			response: "Reset all scores for jeb_",
+		},
+		{
+			name:     "positive case: enable",
+			run:      func(s *Scoreboard) error { return s.Enable(context.Background(), "jeb_", "menu") },
+			command:  "/scoreboard players enable jeb_ menu",
+			response: "Enabled trigger [menu] for jeb_",
+		},
+		{
+			name:     "negative case: enable non-trigger",
+			run:      func(s *Scoreboard) error { return s.Enable(context.Background(), "jeb_", "kills") },
+			command:  "/scoreboard players enable jeb_ kills",
+			response: "Only trigger objectives can be enabled",
+			err:      ErrNotTrigger,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockCommander{responses: map[string]string{tt.command: tt.response}}
+			s := New(m)
+
+			err := tt.run(s)
+			assert.Equal(t, []string{tt.command}, m.commands)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestScoreboard_Tracked(t *testing.T) {
+	cases := []struct {
+		name     string
+		response string
+		expected []string
+		err      error
+	}{
+		{
+			name:     "positive case",
+			response: "There are 2 tracked entity/entities: jeb_, Dinnerbone",
+			expected: []string{"jeb_", "Dinnerbone"},
+		},
+		{
+			name:     "positive case: none",
+			response: "There are no tracked entities",
+			expected: []string{},
+		},
+		{
+			name:     "negative case: unexpected output",
+			response: "There are some tracked entities",
+			err:      &minecraft.UnexpectedOutputError{Command: "scoreboard players list", Output: "There are some tracked entities"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockCommander{responses: map[string]string{"/scoreboard players list": tt.response}}
+			s := New(m)
+
+			actual, err := s.Tracked(context.Background())
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, actual)
+			} else {
+				assert.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
+
+func TestScoreboard_Scores(t *testing.T) {
+	cases := []struct {
+		name     string
+		response string
+		expected map[string]int
+		err      error
+	}{
+		{
+			name:     "positive case",
+			response: "jeb_ has 2 score(s):\n[Kills]: 5\n[Deaths]: -1",
+			expected: map[string]int{"Kills": 5, "Deaths": -1},
+		},
+		{
+			name:     "positive case: joined lines",
+			response: "jeb_ has 2 score(s):[Kills]: 5[Deaths]: -1",
+			expected: map[string]int{"Kills": 5, "Deaths": -1},
+		},
+		{
+			name:     "positive case: none",
+			response: "jeb_ has no scores",
+			expected: map[string]int{},
+		},
+		{
+			name:     "negative case: count mismatch",
+			response: "jeb_ has 3 score(s):[Kills]: 5[Deaths]: -1",
+			err:      &minecraft.UnexpectedOutputError{Command: "scoreboard players list", Output: "jeb_ has 3 score(s):[Kills]: 5[Deaths]: -1"},
+		},
+		{
+			name:     "negative case: unexpected output",
+			response: "jeb_ has many scores",
+			err:      &minecraft.UnexpectedOutputError{Command: "scoreboard players list", Output: "jeb_ has many scores"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockCommander{responses: map[string]string{"/scoreboard players list jeb_": tt.response}}
+			s := New(m)
+
+			actual, err := s.Scores(context.Background(), "jeb_")
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, actual)
+			} else {
+				assert.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package scoreboard
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+)
+
+// TeamOption is an option of a team.
+type TeamOption string
+
+const (
+	TeamColor                  TeamOption = "color"
+	TeamFriendlyFire           TeamOption = "friendlyFire"
+	TeamSeeFriendlyInvisibles  TeamOption = "seeFriendlyInvisibles"
+	TeamNametagVisibility      TeamOption = "nametagVisibility"
+	TeamDeathMessageVisibility TeamOption = "deathMessageVisibility"
+	TeamCollisionRule          TeamOption = "collisionRule"
+	TeamDisplayName            TeamOption = "displayName"
+	TeamPrefix                 TeamOption = "prefix"
+	TeamSuffix                 TeamOption = "suffix"
+)
+
+// Visibility is the value of TeamNametagVisibility and
+// TeamDeathMessageVisibility.
+type Visibility string
+
+const (
+	Always            Visibility = "always"
+	Never             Visibility = "never"
+	HideForOwnTeam    Visibility = "hideForOwnTeam"
+	HideForOtherTeams Visibility = "hideForOtherTeams"
+)
+
+// CollisionRule is the value of TeamCollisionRule.
+type CollisionRule string
+
+const (
+	CollideAlways  CollisionRule = "always"
+	CollideNever   CollisionRule = "never"
+	PushOtherTeams CollisionRule = "pushOtherTeams"
+	PushOwnTeam    CollisionRule = "pushOwnTeam"
+)
+
+var (
+	teamsRe   = regexp.MustCompile(`^There are (\d+) teams?(?:\(s\))?: (.*)$`)
+	membersRe = regexp.MustCompile(`^Team \[.*\] has (\d+) members?(?:\(s\))?: (.*)$`)
+)
+
+// AddTeam creates a team. A nil display name defaults to the name.
+func (s *Scoreboard) AddTeam(ctx context.Context, name string, displayName *minecraft.Text) error {
+	command := "/team add " + name
+	if displayName != nil {
+		command += " " + displayName.String()
+	}
+
+	_, err := s.run(ctx, "team add", command, "Created team")
+	return err
+}
+
+// RemoveTeam removes a team.
+func (s *Scoreboard) RemoveTeam(ctx context.Context, name string) error {
+	_, err := s.run(ctx, "team remove", "/team remove "+name, "Removed team")
+	return err
+}
+
+// EmptyTeam removes all members of a team.
+func (s *Scoreboard) EmptyTeam(ctx context.Context, name string) error {
+	_, err := s.run(ctx, "team empty", "/team empty "+name, "Removed all members")
+	return err
+}
+
+// Teams lists the display names of the teams.
+func (s *Scoreboard) Teams(ctx context.Context) ([]string, error) {
+	res, err := s.run(ctx, "team list", "/team list", "There are")
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(res, "There are no teams") {
+		return []string{}, nil
+	}
+
+	g := teamsRe.FindStringSubmatch(res)
+	if g == nil {
+		return nil, &minecraft.UnexpectedOutputError{Command: "team list", Output: res}
+	}
+
+	return splitList(g[2]), nil
+}
+
+// Members lists the members of a team.
+func (s *Scoreboard) Members(ctx context.Context, team string) ([]string, error) {
+	res, err := s.run(ctx, "team list", "/team list "+team, "Team ", "There are no members")
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(res, "There are no members") {
+		return []string{}, nil
+	}
+
+	g := membersRe.FindStringSubmatch(res)
+	if g == nil {
+		return nil, &minecraft.UnexpectedOutputError{Command: "team list", Output: res}
+	}
+
+	members := splitList(g[2])
+	if n, _ := strconv.Atoi(g[1]); n != len(members) {
+		return nil, &minecraft.UnexpectedOutputError{Command: "team list", Output: res}
+	}
+
+	return members, nil
+}
+
+// Join adds the members, the executor if empty, to a team.
+func (s *Scoreboard) Join(ctx context.Context, team, members string) error {
+	command := "/team join " + team
+	if members != "" {
+		command += " " + members
+	}
+
+	_, err := s.run(ctx, "team join", command, "Added ")
+	return err
+}
+
+// Leave removes the members from their teams.
+func (s *Scoreboard) Leave(ctx context.Context, members string) error {
+	_, err := s.run(ctx, "team leave", "/team leave "+members, "Removed ")
+	return err
+}
+
+// ModifyTeam sets an option of a team. A bool is written as true or false, a
+// minecraft.Text as JSON, and anything else as is.
+func (s *Scoreboard) ModifyTeam(ctx context.Context, team string, option TeamOption, value any) error {
+	var v string
+	switch value := value.(type) {
+	case bool:
+		v = strconv.FormatBool(value)
+	case minecraft.Text:
+		v = value.String()
+	case minecraft.Color:
+		v = string(value)
+	case Visibility:
+		v = string(value)
+	case CollisionRule:
+		v = string(value)
+	case string:
+		v = value
+	default:
+		return &CommandError{Op: "team modify", Err: fmt.Errorf("unsupported value %v", value)}
+	}
+
+	_, err := s.run(ctx, "team modify", "/team modify "+team+" "+string(option)+" "+v, "Updated ", "Enabled ", "Disabled ", "Team ", "Prefix ", "Suffix ")
+	return err
+}
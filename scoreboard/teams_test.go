@@ -0,0 +1,243 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package scoreboard
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreboard_teams(t *testing.T) {
+	red := minecraft.NewText("Red Team").WithColor(minecraft.Red)
+
+	cases := []struct {
+		name     string
+		run      func(s *Scoreboard) error
+		command  string
+		response string
+		err      error
+	}{
+		{
+			name:     "positive case: add",
+			run:      func(s *Scoreboard) error { return s.AddTeam(context.Background(), "red", nil) },
+			command:  "/team add red",
+			response: "Created team [red]",
+		},
+		{
+			name:     "positive case: add with display name",
+			run:      func(s *Scoreboard) error { return s.AddTeam(context.Background(), "red", &red) },
+			command:  `/team add red {"text":"Red Team","color":"red"}`,
+			response: "Created team [Red Team]",
+		},
+		{
+			name:     "negative case: add existing",
+			run:      func(s *Scoreboard) error { return s.AddTeam(context.Background(), "red", nil) },
+			command:  "/team add red",
+			response: "A team already exists by that name",
+			err:      ErrTeamExists,
+		},
+		{
+			name:     "positive case: remove",
+			run:      func(s *Scoreboard) error { return s.RemoveTeam(context.Background(), "red") },
+			command:  "/team remove red",
+			response: "Removed team [red]",
+		},
+		{
+			name:     "negative case: remove unknown",
+			run:      func(s *Scoreboard) error { return s.RemoveTeam(context.Background(), "red") },
+			command:  "/team remove red",
+			response: "Unknown team 'red'",
+			err:      ErrUnknownTeam,
+		},
+		{
+			name:     "positive case: empty",
+			run:      func(s *Scoreboard) error { return s.EmptyTeam(context.Background(), "red") },
+			command:  "/team empty red",
+			response: "Removed all members from team [red]",
+		},
+		{
+			name:     "positive case: join",
+			run:      func(s *Scoreboard) error { return s.Join(context.Background(), "red", "jeb_") },
+			command:  "/team join red jeb_",
+			response: "Added jeb_ to team [red]",
+		},
+		{
+			name:     "positive case: leave",
+			run:      func(s *Scoreboard) error { return s.Leave(context.Background(), "jeb_") },
+			command:  "/team leave jeb_",
+			response: "Removed jeb_ from any team",
+		},
+		{
+			name:     "positive case: modify color",
+			run:      func(s *Scoreboard) error { return s.ModifyTeam(context.Background(), "red", TeamColor, minecraft.Red) },
+			command:  "/team modify red color red",
+			response: "Updated the color for team [red] to red",
+		},
+		{
+			name:     "positive case: modify friendly fire",
+			run:      func(s *Scoreboard) error { return s.ModifyTeam(context.Background(), "red", TeamFriendlyFire, false) },
+			command:  "/team modify red friendlyFire false",
+			response: "Disabled friendly fire for team [red]",
+		},
+		{
+			name: "positive case: modify prefix",
+			run: func(s *Scoreboard) error {
+				return s.ModifyTeam(context.Background(), "red", TeamPrefix, minecraft.NewText("[R] "))
+			},
+			command:  `/team modify red prefix {"text":"[R] "}`,
+			response: "Team prefix set to [R] ",
+		},
+		{
+			name: "positive case: modify nametag visibility",
+			run: func(s *Scoreboard) error {
+				return s.ModifyTeam(context.Background(), "red", TeamNametagVisibility, HideForOtherTeams)
+			},
+			command:  "/team modify red nametagVisibility hideForOtherTeams",
+			response: "Updated the nametag visibility for team [red] to \"Hide for other teams\"",
+		},
+		{
+			name: "positive case: modify unchanged",
+			run: func(s *Scoreboard) error {
+				return s.ModifyTeam(context.Background(), "red", TeamCollisionRule, PushOwnTeam)
+			},
+			command:  "/team modify red collisionRule pushOwnTeam",
+			response: "Nothing changed. Collision rule is already that value",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockCommander{responses: map[string]string{tt.command: tt.response}}
+			s := New(m)
+
+			err := tt.run(s)
+			assert.Equal(t, []string{tt.command}, m.commands)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestScoreboard_ModifyTeam_unsupported(t *testing.T) {
+	m := &mockCommander{responses: map[string]string{}}
+	s := New(m)
+
+	err := s.ModifyTeam(context.Background(), "red", TeamColor, 1)
+	assert.EqualError(t, err, "scoreboard team modify: unsupported value 1")
+	assert.Empty(t, m.commands)
+}
+
+func TestScoreboard_Teams(t *testing.T) {
+	cases := []struct {
+		name     string
+		response string
+		expected []string
+		err      error
+	}{
+		{
+			name:     "positive case",
+			response: "There are 2 team(s): [Red Team], [blue]",
+			expected: []string{"Red Team", "blue"},
+		},
+		{
+			name:     "positive case: none",
+			response: "There are no teams",
+			expected: []string{},
+		},
+		{
+			name:     "negative case: unexpected output",
+			response: "There are some teams",
+			err:      &minecraft.UnexpectedOutputError{Command: "team list", Output: "There are some teams"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockCommander{responses: map[string]string{"/team list": tt.response}}
+			s := New(m)
+
+			actual, err := s.Teams(context.Background())
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, actual)
+			} else {
+				assert.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
+
+func TestScoreboard_Members(t *testing.T) {
+	cases := []struct {
+		name     string
+		response string
+		expected []string
+		err      error
+		target   error
+	}{
+		{
+			name:     "positive case",
+			response: "Team [Red Team] has 2 member(s): jeb_, Dinnerbone",
+			expected: []string{"jeb_", "Dinnerbone"},
+		},
+		{
+			name:     "positive case: none",
+			response: "There are no members on team [Red Team]",
+			expected: []string{},
+		},
+		{
+			name:     "negative case: unknown team",
+			response: "Unknown team 'red'",
+			target:   ErrUnknownTeam,
+		},
+		{
+			name:     "negative case: count mismatch",
+			response: "Team [Red Team] has 3 member(s): jeb_, Dinnerbone",
+			err:      &minecraft.UnexpectedOutputError{Command: "team list", Output: "Team [Red Team] has 3 member(s): jeb_, Dinnerbone"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockCommander{responses: map[string]string{"/team list red": tt.response}}
+			s := New(m)
+
+			actual, err := s.Members(context.Background(), "red")
+			switch {
+			case tt.target != nil:
+				assert.ErrorIs(t, err, tt.target)
+			case tt.err != nil:
+				assert.Equal(t, tt.err, err)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package scoreboard
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+)
+
+var ErrNoObjectives = errors.New("no trigger objectives")
+
+// TriggerConfig configures a Subscriber.
+type TriggerConfig struct {
+	// Objectives are the trigger objectives to poll.
+	Objectives []string
+	// Interval between polls. It defaults to 1 second.
+	Interval time.Duration
+	// Timeout of each command sent by a poll. It defaults to 10 seconds.
+	Timeout time.Duration
+	// Buffer is the capacity of the event channel. It defaults to 64.
+	Buffer int
+	// OnError is called with the errors of polls. Polling continues after an
+	// error.
+	OnError func(err error)
+}
+
+// TriggerEvent is a player running /trigger.
+type TriggerEvent struct {
+	Player    string
+	Objective string
+	Value     int
+}
+
+// Subscriber polls trigger objectives and emits an event for every player
+// with a nonzero score, then resets the score and enables the trigger again.
+//
+// The server disables a trigger for a player once they use it, so the score
+// cannot change between the poll and the reset. An event is delivered before
+// the score is reset: if the subscriber is closed while the event channel is
+// full, the score is left as is and the event is emitted by the next
+// subscriber. An event whose reset fails is emitted again by the next poll.
+// When the channel is full, polling waits for the consumer for as long as it
+// takes instead of dropping events; Timeout does not apply to the wait.
+type Subscriber struct {
+	s   *Scoreboard
+	cfg TriggerConfig
+
+	events chan TriggerEvent
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+	wg     sync.WaitGroup
+}
+
+// Subscribe starts polling the trigger objectives until Close.
+func (s *Scoreboard) Subscribe(cfg TriggerConfig) (*Subscriber, error) {
+	if len(cfg.Objectives) == 0 {
+		return nil, &CommandError{Op: "subscribe", Err: ErrNoObjectives}
+	}
+
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = 64
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub := &Subscriber{
+		s:      s,
+		cfg:    cfg,
+		events: make(chan TriggerEvent, cfg.Buffer),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	sub.wg.Add(1)
+	go sub.loop()
+
+	return sub, nil
+}
+
+// Events returns the channel of events. It is closed after Close.
+func (sub *Subscriber) Events() <-chan TriggerEvent {
+	return sub.events
+}
+
+// Close stops polling and waits for the current poll to return.
+func (sub *Subscriber) Close() error {
+	sub.once.Do(sub.cancel)
+	sub.wg.Wait()
+
+	return nil
+}
+
+func (sub *Subscriber) loop() {
+	defer sub.wg.Done()
+	defer close(sub.events)
+
+	ticker := time.NewTicker(sub.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		sub.poll()
+
+		select {
+		case <-sub.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (sub *Subscriber) poll() {
+	for _, objective := range sub.cfg.Objectives {
+		if err := sub.drain(objective); err != nil {
+			if sub.ctx.Err() != nil {
+				return
+			}
+
+			if sub.cfg.OnError != nil {
+				sub.cfg.OnError(err)
+			}
+		}
+	}
+}
+
+// timeout returns the context of a single command.
+func (sub *Subscriber) timeout() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(sub.ctx, sub.cfg.Timeout)
+}
+
+// drain emits the pending triggers of an objective, then enables it for the
+// players who have not used it yet.
+func (sub *Subscriber) drain(objective string) error {
+	seen := map[string]bool{}
+
+	for _, r := range []minecraft.Range{minecraft.AtLeast(1), minecraft.AtMost(-1)} {
+		target := minecraft.AllPlayers().Score(objective, r).Limit(1).String()
+
+		for {
+			ctx, cancel := sub.timeout()
+			score, err := sub.s.score(ctx, target, objective)
+			cancel()
+			if errors.Is(err, ErrNotFound) {
+				break
+			}
+
+			if err != nil {
+				return err
+			}
+
+			// NOTE: a player who triggers again while the objective is
+			// drained is left for the next poll.
+			if seen[score.Target] {
+				break
+			}
+			seen[score.Target] = true
+
+			select {
+			case <-sub.ctx.Done():
+				return sub.ctx.Err()
+			case sub.events <- TriggerEvent{Player: score.Target, Objective: objective, Value: score.Value}:
+			}
+
+			if err := sub.reset(score.Target, objective); err != nil {
+				return err
+			}
+		}
+	}
+
+	ctx, cancel := sub.timeout()
+	defer cancel()
+
+	// Players who joined since the last poll have no score, which selectors
+	// do not match, so give everyone a score before enabling.
+	if err := sub.s.Add(ctx, "@a", objective, 0); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	target := minecraft.AllPlayers().Score(objective, minecraft.Exactly(0)).String()
+	if err := sub.s.Enable(ctx, target, objective); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// reset resets the score of a player to zero and enables the trigger again.
+func (sub *Subscriber) reset(player, objective string) error {
+	ctx, cancel := sub.timeout()
+	defer cancel()
+
+	if err := sub.s.Set(ctx, player, objective, 0); err != nil {
+		return err
+	}
+
+	return sub.s.Enable(ctx, player, objective)
+}
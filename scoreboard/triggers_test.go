@@ -0,0 +1,328 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package scoreboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aton-Kish/gorcon/minecraft"
+	"github.com/stretchr/testify/assert"
+)
+
+// triggerServer simulates the trigger objectives of a server.
+type triggerServer struct {
+	mu      sync.Mutex
+	players []string
+	scores  map[string]map[string]int
+	enabled map[string]map[string]bool
+}
+
+func newTriggerServer(players ...string) *triggerServer {
+	return &triggerServer{players: players, scores: map[string]map[string]int{}, enabled: map[string]map[string]bool{}}
+}
+
+// trigger runs /trigger for a player.
+func (s *triggerServer) trigger(player, objective string, value int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scores[objective][player] = value
+	delete(s.enabled[objective], player)
+}
+
+func (s *triggerServer) score(player, objective string) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.scores[objective][player]
+	return v, ok
+}
+
+func (s *triggerServer) isEnabled(player, objective string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.enabled[objective][player]
+}
+
+// match returns the players matching a name or a selector.
+func (s *triggerServer) match(target, objective string) []string {
+	if !strings.HasPrefix(target, "@") {
+		return []string{target}
+	}
+
+	sel, err := minecraft.ParseSelector(target)
+	if err != nil {
+		panic(err)
+	}
+
+	matched := []string{}
+	for _, p := range s.players {
+		ok := true
+		for _, sr := range sel.Scores {
+			v, has := s.scores[sr.Objective][p]
+			if !has || (sr.Range.Min != nil && float64(v) < *sr.Range.Min) || (sr.Range.Max != nil && float64(v) > *sr.Range.Max) {
+				ok = false
+			}
+		}
+
+		if ok {
+			matched = append(matched, p)
+		}
+	}
+
+	sort.Strings(matched)
+
+	return matched
+}
+
+func (s *triggerServer) Command(command string) (string, error) {
+	return s.CommandContext(context.Background(), command)
+}
+
+func (s *triggerServer) CommandContext(ctx context.Context, command string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f := strings.Fields(strings.TrimPrefix(command, "/scoreboard players "))
+	op, target, objective := f[0], f[1], f[2]
+
+	if _, ok := s.scores[objective]; !ok {
+		return fmt.Sprintf("Unknown scoreboard objective '%s'", objective), nil
+	}
+
+	players := s.match(target, objective)
+	if len(players) == 0 {
+		return "No player was found", nil
+	}
+
+	switch op {
+	case "get":
+		p := players[0]
+		return fmt.Sprintf("%s has %d [%s]", p, s.scores[objective][p], objective), nil
+	case "set":
+		v, _ := strconv.Atoi(f[3])
+		for _, p := range players {
+			s.scores[objective][p] = v
+		}
+		return fmt.Sprintf("Set [%s] for %d entities to %d", objective, len(players), v), nil
+	case "add":
+		v, _ := strconv.Atoi(f[3])
+		for _, p := range players {
+			s.scores[objective][p] += v
+		}
+		return fmt.Sprintf("Added %d to [%s] for %d entities", v, objective, len(players)), nil
+	case "enable":
+		for _, p := range players {
+			s.enabled[objective][p] = true
+		}
+		return fmt.Sprintf("Enabled trigger [%s] for %d entities", objective, len(players)), nil
+	}
+
+	return "", errors.New("unknown command")
+}
+
+// collect receives n events or fails after a second.
+func collect(t *testing.T, events <-chan TriggerEvent, n int) []TriggerEvent {
+	t.Helper()
+
+	received := []TriggerEvent{}
+	timeout := time.After(time.Second)
+	for len(received) < n {
+		select {
+		case e := <-events:
+			received = append(received, e)
+		case <-timeout:
+			t.Fatalf("received %d of %d events", len(received), n)
+		}
+	}
+
+	return received
+}
+
+func TestScoreboard_Subscribe(t *testing.T) {
+	srv := newTriggerServer("jeb_", "Dinnerbone", "Grumm")
+	srv.scores["menu"] = map[string]int{}
+	srv.enabled["menu"] = map[string]bool{}
+	srv.scores["vote"] = map[string]int{}
+	srv.enabled["vote"] = map[string]bool{}
+
+	srv.trigger("jeb_", "menu", 3)
+	srv.trigger("Dinnerbone", "menu", -1)
+	srv.trigger("Dinnerbone", "vote", 1)
+
+	sub, err := New(srv).Subscribe(TriggerConfig{Objectives: []string{"menu", "vote"}, Interval: 10 * time.Millisecond})
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	expected := []TriggerEvent{
+		{Player: "jeb_", Objective: "menu", Value: 3},
+		{Player: "Dinnerbone", Objective: "menu", Value: -1},
+		{Player: "Dinnerbone", Objective: "vote", Value: 1},
+	}
+	assert.Equal(t, expected, collect(t, sub.Events(), 3))
+
+	assert.Eventually(t, func() bool {
+		for _, p := range []string{"jeb_", "Dinnerbone", "Grumm"} {
+			for _, o := range []string{"menu", "vote"} {
+				if v, ok := srv.score(p, o); !ok || v != 0 || !srv.isEnabled(p, o) {
+					return false
+				}
+			}
+		}
+		return true
+	}, time.Second, 10*time.Millisecond)
+
+	srv.trigger("Grumm", "vote", 2)
+	assert.Equal(t, []TriggerEvent{{Player: "Grumm", Objective: "vote", Value: 2}}, collect(t, sub.Events(), 1))
+}
+
+func TestScoreboard_Subscribe_noObjectives(t *testing.T) {
+	_, err := New(newTriggerServer()).Subscribe(TriggerConfig{})
+	assert.ErrorIs(t, err, ErrNoObjectives)
+}
+
+func TestSubscriber_onError(t *testing.T) {
+	srv := newTriggerServer("jeb_")
+	srv.scores["menu"] = map[string]int{}
+	srv.enabled["menu"] = map[string]bool{}
+	srv.trigger("jeb_", "menu", 1)
+
+	errs := make(chan error, 16)
+	sub, err := New(srv).Subscribe(TriggerConfig{
+		Objectives: []string{"unknown", "menu"},
+		Interval:   10 * time.Millisecond,
+		OnError: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	})
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	assert.Equal(t, []TriggerEvent{{Player: "jeb_", Objective: "menu", Value: 1}}, collect(t, sub.Events(), 1))
+
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, ErrUnknownObjective)
+	case <-time.After(time.Second):
+		t.Fatal("no error reported")
+	}
+}
+
+func TestSubscriber_backpressure(t *testing.T) {
+	srv := newTriggerServer("jeb_", "Dinnerbone")
+	srv.scores["menu"] = map[string]int{}
+	srv.enabled["menu"] = map[string]bool{}
+	srv.trigger("jeb_", "menu", 1)
+	srv.trigger("Dinnerbone", "menu", 2)
+
+	sub, err := New(srv).Subscribe(TriggerConfig{Objectives: []string{"menu"}, Interval: 10 * time.Millisecond, Buffer: 1})
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		v, _ := srv.score("Dinnerbone", "menu")
+		return v == 0
+	}, time.Second, 10*time.Millisecond)
+
+	// The buffer is full, so the second trigger is not acknowledged.
+	time.Sleep(50 * time.Millisecond)
+	v, _ := srv.score("jeb_", "menu")
+	assert.Equal(t, 1, v)
+	assert.False(t, srv.isEnabled("jeb_", "menu"))
+
+	assert.NoError(t, sub.Close())
+
+	received := []TriggerEvent{}
+	for e := range sub.Events() {
+		received = append(received, e)
+	}
+	assert.Equal(t, []TriggerEvent{{Player: "Dinnerbone", Objective: "menu", Value: 2}}, received)
+
+	v, _ = srv.score("jeb_", "menu")
+	assert.Equal(t, 1, v)
+}
+
+func TestSubscriber_slowConsumer(t *testing.T) {
+	srv := newTriggerServer("jeb_", "Dinnerbone")
+	srv.scores["menu"] = map[string]int{}
+	srv.enabled["menu"] = map[string]bool{}
+	srv.trigger("jeb_", "menu", 1)
+	srv.trigger("Dinnerbone", "menu", 2)
+
+	errs := make(chan error, 16)
+	sub, err := New(srv).Subscribe(TriggerConfig{
+		Objectives: []string{"menu"},
+		Interval:   10 * time.Millisecond,
+		Timeout:    20 * time.Millisecond,
+		Buffer:     1,
+		OnError: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	})
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	// NOTE: the wait for the consumer outlasts Timeout
+	time.Sleep(100 * time.Millisecond)
+
+	expected := []TriggerEvent{
+		{Player: "Dinnerbone", Objective: "menu", Value: 2},
+		{Player: "jeb_", Objective: "menu", Value: 1},
+	}
+	assert.Equal(t, expected, collect(t, sub.Events(), 2))
+
+	select {
+	case err := <-errs:
+		t.Fatalf("unexpected error: %v", err)
+	default:
+	}
+}
+
+func TestSubscriber_Close(t *testing.T) {
+	srv := newTriggerServer()
+	srv.scores["menu"] = map[string]int{}
+	srv.enabled["menu"] = map[string]bool{}
+
+	sub, err := New(srv).Subscribe(TriggerConfig{Objectives: []string{"menu"}, Interval: 10 * time.Millisecond})
+	assert.NoError(t, err)
+
+	assert.NoError(t, sub.Close())
+	assert.NoError(t, sub.Close())
+
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+}
@@ -0,0 +1,322 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snbt
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// UnmarshalTypeError is returned when a tag cannot be stored in a Go value.
+type UnmarshalTypeError struct {
+	Value string
+	Type  reflect.Type
+	Field string
+}
+
+func (e *UnmarshalTypeError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	if e.Field != "" {
+		return fmt.Sprintf("snbt: cannot unmarshal %s into field %s of type %s", e.Value, e.Field, e.Type)
+	}
+
+	return fmt.Sprintf("snbt: cannot unmarshal %s into Go value of type %s", e.Value, e.Type)
+}
+
+// InvalidUnmarshalError is returned when the argument of Unmarshal is not a
+// non-nil pointer.
+type InvalidUnmarshalError struct {
+	Type reflect.Type
+}
+
+func (e *InvalidUnmarshalError) Error() string {
+	if e == nil || e.Type == nil {
+		return "snbt: Unmarshal(nil)"
+	}
+
+	return fmt.Sprintf("snbt: Unmarshal(non-pointer %s)", e.Type)
+}
+
+// Unmarshal parses s and stores the result in the value pointed to by v.
+//
+// Compounds are stored in structs or maps with string keys. Struct fields are
+// matched by the key in their `nbt` tag, or by their name; a tag of "-" skips
+// the field. Fields whose keys are missing are left untouched, so pointer
+// fields can mark optional data. Lists and typed arrays are stored in slices
+// or arrays, numbers in any numeric type, and bytes also in bools. An int
+// array of length 4, the encoding of UUIDs, is stored in a string as a
+// hyphenated UUID. An interface{} receives the values listed in the package
+// documentation.
+func Unmarshal(s string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return &InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+
+	tag, err := Parse(s)
+	if err != nil {
+		return err
+	}
+
+	return decode(tag, rv.Elem(), "")
+}
+
+func decode(tag any, v reflect.Value, field string) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return decode(tag, v.Elem(), field)
+	}
+
+	if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+		v.Set(reflect.ValueOf(tag))
+		return nil
+	}
+
+	mismatch := func() error {
+		return &UnmarshalTypeError{Value: describe(tag), Type: v.Type(), Field: field}
+	}
+
+	switch t := tag.(type) {
+	case map[string]any:
+		return decodeCompound(t, v, field, mismatch)
+	case []any:
+		return decodeList(len(t), func(i int, e reflect.Value) error { return decode(t[i], e, field) }, v, mismatch)
+	case []int8:
+		return decodeList(len(t), func(i int, e reflect.Value) error { return decode(t[i], e, field) }, v, mismatch)
+	case []int32:
+		if v.Kind() == reflect.String && len(t) == 4 {
+			v.SetString(formatUUID(t))
+			return nil
+		}
+		return decodeList(len(t), func(i int, e reflect.Value) error { return decode(t[i], e, field) }, v, mismatch)
+	case []int64:
+		return decodeList(len(t), func(i int, e reflect.Value) error { return decode(t[i], e, field) }, v, mismatch)
+	case string:
+		if v.Kind() != reflect.String {
+			return mismatch()
+		}
+		v.SetString(t)
+		return nil
+	default:
+		return decodeNumber(tag, v, mismatch)
+	}
+}
+
+func decodeCompound(m map[string]any, v reflect.Value, field string, mismatch func() error) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+
+			key := fieldKey(f)
+			if key == "-" {
+				continue
+			}
+
+			tag, ok := m[key]
+			if !ok {
+				continue
+			}
+
+			name := f.Name
+			if field != "" {
+				name = field + "." + f.Name
+			}
+
+			if err := decode(tag, v.Field(i), name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return mismatch()
+		}
+
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), len(m)))
+		}
+
+		for k, tag := range m {
+			e := reflect.New(v.Type().Elem()).Elem()
+			if err := decode(tag, e, field); err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), e)
+		}
+
+		return nil
+	default:
+		return mismatch()
+	}
+}
+
+func decodeList(n int, elem func(i int, e reflect.Value) error, v reflect.Value, mismatch func() error) error {
+	switch v.Kind() {
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), n, n)
+		for i := 0; i < n; i++ {
+			if err := elem(i, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+
+		return nil
+	case reflect.Array:
+		if v.Len() != n {
+			return mismatch()
+		}
+
+		for i := 0; i < n; i++ {
+			if err := elem(i, v.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	default:
+		return mismatch()
+	}
+}
+
+func decodeNumber(tag any, v reflect.Value, mismatch func() error) error {
+	var (
+		n       int64
+		f       float64
+		integer bool
+	)
+	switch t := tag.(type) {
+	case int8:
+		n, integer = int64(t), true
+	case int16:
+		n, integer = int64(t), true
+	case int32:
+		n, integer = int64(t), true
+	case int64:
+		n, integer = t, true
+	case float32:
+		f = float64(t)
+	case float64:
+		f = t
+	default:
+		return mismatch()
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !integer || v.OverflowInt(n) {
+			return mismatch()
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !integer || n < 0 || v.OverflowUint(uint64(n)) {
+			return mismatch()
+		}
+		v.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		if integer {
+			f = float64(n)
+		}
+		if v.Kind() == reflect.Float32 && math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+			return mismatch()
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		if _, ok := tag.(int8); !ok {
+			return mismatch()
+		}
+		v.SetBool(n != 0)
+	default:
+		return mismatch()
+	}
+
+	return nil
+}
+
+// fieldKey returns the compound key of a struct field.
+func fieldKey(f reflect.StructField) string {
+	key, _, _ := strings.Cut(f.Tag.Get("nbt"), ",")
+	if key == "" {
+		return f.Name
+	}
+
+	return key
+}
+
+// formatUUID formats the int array encoding of a UUID.
+func formatUUID(a []int32) string {
+	var b [16]byte
+	for i, n := range a {
+		u := uint32(n)
+		b[4*i] = byte(u >> 24)
+		b[4*i+1] = byte(u >> 16)
+		b[4*i+2] = byte(u >> 8)
+		b[4*i+3] = byte(u)
+	}
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// describe names the tag type in errors.
+func describe(tag any) string {
+	switch tag.(type) {
+	case int8:
+		return "byte"
+	case int16:
+		return "short"
+	case int32:
+		return "int"
+	case int64:
+		return "long"
+	case float32:
+		return "float"
+	case float64:
+		return "double"
+	case string:
+		return "string"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "compound"
+	case []int8:
+		return "byte array"
+	case []int32:
+		return "int array"
+	case []int64:
+		return "long array"
+	default:
+		return fmt.Sprintf("%T", tag)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package snbt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type item struct {
+	Slot  int8   `nbt:"Slot"`
+	ID    string `nbt:"id"`
+	Count int    `nbt:"count"`
+}
+
+type player struct {
+	Pos        [3]float64     `nbt:"Pos"`
+	Health     float32        `nbt:"Health"`
+	UUID       string         `nbt:"UUID"`
+	Inventory  []item         `nbt:"Inventory"`
+	OnGround   bool           `nbt:"OnGround"`
+	Dimension  string         `nbt:"Dimension"`
+	Seen       []int64        `nbt:"Seen"`
+	SpawnX     *int           `nbt:"SpawnX"`
+	Attributes map[string]any `nbt:"attributes"`
+	Ignored    string         `nbt:"-"`
+}
+
+const playerSNBT = `{Pos: [0.5d, 64.0d, -0.5d], Health: 20.0f, UUID: [I; -2059632401, 1010256381, -1438018677, 1732958950], ` +
+	`Inventory: [{Slot: 0b, id: "minecraft:dirt", count: 64}, {Slot: 8b, id: "minecraft:torch", count: 3}], ` +
+	`OnGround: 1b, Dimension: "minecraft:overworld", Seen: [L; 1L, 2L], attributes: {speed: 0.1d}, Ignored: "x"}`
+
+func TestUnmarshal(t *testing.T) {
+	var p player
+	err := Unmarshal(playerSNBT, &p)
+
+	assert.NoError(t, err)
+	assert.Equal(t, player{
+		Pos:        [3]float64{0.5, 64, -0.5},
+		Health:     20,
+		UUID:       "853c80ef-3c37-49fd-aa49-938b674adae6",
+		Inventory:  []item{{Slot: 0, ID: "minecraft:dirt", Count: 64}, {Slot: 8, ID: "minecraft:torch", Count: 3}},
+		OnGround:   true,
+		Dimension:  "minecraft:overworld",
+		Seen:       []int64{1, 2},
+		SpawnX:     nil,
+		Attributes: map[string]any{"speed": 0.1},
+	}, p)
+}
+
+func TestUnmarshal_optional(t *testing.T) {
+	var p player
+	assert.NoError(t, Unmarshal(`{SpawnX: 10}`, &p))
+	if assert.NotNil(t, p.SpawnX) {
+		assert.Equal(t, 10, *p.SpawnX)
+	}
+}
+
+func TestUnmarshal_values(t *testing.T) {
+	cases := []struct {
+		name     string
+		s        string
+		v        any
+		expected any
+		err      error
+	}{
+		{
+			name:     "positive case: int into float",
+			s:        "3",
+			v:        new(float64),
+			expected: 3.0,
+		},
+		{
+			name:     "positive case: byte array into slice",
+			s:        "[B; 1b, 2b]",
+			v:        new([]uint8),
+			expected: []uint8{1, 2},
+		},
+		{
+			name:     "positive case: int array into slice",
+			s:        "[I; 1, 2, 3, 4]",
+			v:        new([]int32),
+			expected: []int32{1, 2, 3, 4},
+		},
+		{
+			name:     "positive case: interface",
+			s:        "[I; 1, 2, 3, 4]",
+			v:        new(any),
+			expected: []int32{1, 2, 3, 4},
+		},
+		{
+			name:     "positive case: map",
+			s:        "{a: 1, b: 2}",
+			v:        new(map[string]int),
+			expected: map[string]int{"a": 1, "b": 2},
+		},
+		{
+			name: "negative case: overflow",
+			s:    "300",
+			v:    new(int8),
+			err:  &UnmarshalTypeError{},
+		},
+		{
+			name: "negative case: negative into unsigned",
+			s:    "-1b",
+			v:    new(uint8),
+			err:  &UnmarshalTypeError{},
+		},
+		{
+			name: "negative case: double into int",
+			s:    "1.5d",
+			v:    new(int),
+			err:  &UnmarshalTypeError{},
+		},
+		{
+			name: "negative case: int into bool",
+			s:    "1",
+			v:    new(bool),
+			err:  &UnmarshalTypeError{},
+		},
+		{
+			name: "negative case: string into int",
+			s:    `"1"`,
+			v:    new(int),
+			err:  &UnmarshalTypeError{},
+		},
+		{
+			name: "negative case: array length",
+			s:    "[1, 2]",
+			v:    new([3]int),
+			err:  &UnmarshalTypeError{},
+		},
+		{
+			name: "negative case: syntax error",
+			s:    "{",
+			v:    new(any),
+			err:  &SyntaxError{},
+		},
+		{
+			name: "negative case: non-pointer",
+			s:    "1",
+			v:    0,
+			err:  &InvalidUnmarshalError{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Unmarshal(tt.s, tt.v)
+
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, reflectElem(tt.v))
+			} else {
+				assert.IsType(t, tt.err, err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalTypeError_Error(t *testing.T) {
+	var p player
+	err := Unmarshal(`{Inventory: [{Slot: "a"}]}`, &p)
+	assert.EqualError(t, err, "snbt: cannot unmarshal string into field Inventory.Slot of type int8")
+
+	var n int
+	err = Unmarshal(`"a"`, &n)
+	assert.EqualError(t, err, "snbt: cannot unmarshal string into Go value of type int")
+}
+
+func reflectElem(v any) any {
+	switch v := v.(type) {
+	case *float64:
+		return *v
+	case *[]uint8:
+		return *v
+	case *[]int32:
+		return *v
+	case *any:
+		return *v
+	case *map[string]int:
+		return *v
+	default:
+		return v
+	}
+}
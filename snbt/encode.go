@@ -0,0 +1,372 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snbt
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UnsupportedTypeError is returned when Marshal encounters a type that has no
+// SNBT encoding.
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("snbt: unsupported type: %s", e.Type)
+}
+
+// UnsupportedValueError is returned when Marshal encounters a value that has
+// no SNBT encoding, such as NaN or an int out of range.
+type UnsupportedValueError struct {
+	Str string
+}
+
+func (e *UnsupportedValueError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("snbt: unsupported value: %s", e.Str)
+}
+
+// Marshal returns the SNBT encoding of v, the reverse of Unmarshal.
+//
+// Go numbers keep their width through the suffixes b, s, L, f and d; int,
+// uint and uint32 are encoded as ints, and unsigned values must fit in the
+// signed tag of their width. Bools are encoded as bytes. Slices of int8 or
+// uint8, int32 and int64 are encoded as typed arrays, other slices and arrays
+// as lists, and maps with string keys and structs as compounds with sorted
+// keys. Struct fields follow the `nbt` tag of Unmarshal, with the options
+// "omitempty", which omits zero values, and "uuid", which encodes a
+// hyphenated UUID string as an int array. Nil pointers and interfaces in
+// structs and maps are omitted.
+func Marshal(v any) (string, error) {
+	var b strings.Builder
+	if err := encode(&b, reflect.ValueOf(v)); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// Quote returns s as an SNBT string, quoting and escaping it unless it can be
+// written unquoted without being read back as another type.
+func Quote(s string) string {
+	if isPlain(s) {
+		return s
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// isPlain reports whether s can be written unquoted as a string.
+func isPlain(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if !isUnquoted(s[i]) {
+			return false
+		}
+	}
+
+	p := &parser{}
+	_, isString := p.scalar(s).(string)
+
+	return isString
+}
+
+func encode(b *strings.Builder, v reflect.Value) error {
+	if !v.IsValid() {
+		return &UnsupportedValueError{Str: "nil"}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return &UnsupportedValueError{Str: "nil " + v.Type().String()}
+		}
+		return encode(b, v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			b.WriteString("1b")
+		} else {
+			b.WriteString("0b")
+		}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64:
+		s, err := formatInt(v)
+		if err != nil {
+			return err
+		}
+		b.WriteString(s)
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return &UnsupportedValueError{Str: strconv.FormatFloat(f, 'g', -1, 64)}
+		}
+
+		if v.Kind() == reflect.Float32 {
+			b.WriteString(formatFloat(f, 32) + "f")
+		} else {
+			b.WriteString(formatFloat(f, 64) + "d")
+		}
+	case reflect.String:
+		b.WriteString(Quote(v.String()))
+	case reflect.Slice, reflect.Array:
+		return encodeList(b, v)
+	case reflect.Map:
+		return encodeMap(b, v)
+	case reflect.Struct:
+		return encodeStruct(b, v)
+	default:
+		return &UnsupportedTypeError{Type: v.Type()}
+	}
+
+	return nil
+}
+
+func formatInt(v reflect.Value) (string, error) {
+	var (
+		bits   int
+		suffix string
+	)
+	switch v.Kind() {
+	case reflect.Int8, reflect.Uint8:
+		bits, suffix = 8, "b"
+	case reflect.Int16, reflect.Uint16:
+		bits, suffix = 16, "s"
+	case reflect.Int32, reflect.Uint32, reflect.Int, reflect.Uint:
+		bits = 32
+	default:
+		bits, suffix = 64, "L"
+	}
+
+	var n int64
+	if v.CanInt() {
+		n = v.Int()
+	} else {
+		u := v.Uint()
+		if u > math.MaxInt64 {
+			return "", &UnsupportedValueError{Str: strconv.FormatUint(u, 10)}
+		}
+		n = int64(u)
+	}
+
+	if limit := int64(1) << (bits - 1); bits < 64 && (n < -limit || n >= limit) {
+		return "", &UnsupportedValueError{Str: strconv.FormatInt(n, 10)}
+	}
+
+	return strconv.FormatInt(n, 10) + suffix, nil
+}
+
+// formatFloat formats f so that it is always read back as a decimal.
+func formatFloat(f float64, bits int) string {
+	s := strconv.FormatFloat(f, 'g', -1, bits)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+
+	return s
+}
+
+func encodeList(b *strings.Builder, v reflect.Value) error {
+	prefix := ""
+	switch v.Type().Elem().Kind() {
+	case reflect.Int8, reflect.Uint8:
+		prefix = "B;"
+	case reflect.Int32:
+		prefix = "I;"
+	case reflect.Int64:
+		prefix = "L;"
+	}
+
+	b.WriteByte('[')
+	b.WriteString(prefix)
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		} else if prefix != "" {
+			b.WriteByte(' ')
+		}
+
+		if err := encode(b, v.Index(i)); err != nil {
+			return err
+		}
+	}
+	b.WriteByte(']')
+
+	return nil
+}
+
+func encodeMap(b *strings.Builder, v reflect.Value) error {
+	if v.Type().Key().Kind() != reflect.String {
+		return &UnsupportedTypeError{Type: v.Type()}
+	}
+
+	keys := make([]string, 0, v.Len())
+	values := make(map[string]reflect.Value, v.Len())
+	for it := v.MapRange(); it.Next(); {
+		if isNil(it.Value()) {
+			continue
+		}
+
+		k := it.Key().String()
+		keys = append(keys, k)
+		values[k] = it.Value()
+	}
+	sort.Strings(keys)
+
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		b.WriteString(Quote(k))
+		b.WriteString(": ")
+		if err := encode(b, values[k]); err != nil {
+			return err
+		}
+	}
+	b.WriteByte('}')
+
+	return nil
+}
+
+func encodeStruct(b *strings.Builder, v reflect.Value) error {
+	type entry struct {
+		key   string
+		value reflect.Value
+		uuid  bool
+	}
+
+	t := v.Type()
+	entries := make([]entry, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		key := fieldKey(f)
+		if key == "-" {
+			continue
+		}
+
+		fv := v.Field(i)
+		opts := fieldOptions(f)
+		if isNil(fv) || opts["omitempty"] && fv.IsZero() {
+			continue
+		}
+
+		entries = append(entries, entry{key: key, value: fv, uuid: opts["uuid"]})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	b.WriteByte('{')
+	for i, e := range entries {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		b.WriteString(Quote(e.key))
+		b.WriteString(": ")
+
+		if e.uuid {
+			a, err := parseUUID(reflect.Indirect(e.value))
+			if err != nil {
+				return err
+			}
+			b.WriteString(a)
+			continue
+		}
+
+		if err := encode(b, e.value); err != nil {
+			return err
+		}
+	}
+	b.WriteByte('}')
+
+	return nil
+}
+
+// parseUUID encodes a hyphenated UUID string as an int array.
+func parseUUID(v reflect.Value) (string, error) {
+	if v.Kind() != reflect.String {
+		return "", &UnsupportedTypeError{Type: v.Type()}
+	}
+
+	raw, err := hex.DecodeString(strings.ReplaceAll(v.String(), "-", ""))
+	if err != nil || len(raw) != 16 {
+		return "", &UnsupportedValueError{Str: strconv.Quote(v.String())}
+	}
+
+	ints := make([]string, 4)
+	for i := range ints {
+		n := int32(uint32(raw[4*i])<<24 | uint32(raw[4*i+1])<<16 | uint32(raw[4*i+2])<<8 | uint32(raw[4*i+3]))
+		ints[i] = strconv.FormatInt(int64(n), 10)
+	}
+
+	return "[I; " + strings.Join(ints, ", ") + "]", nil
+}
+
+func fieldOptions(f reflect.StructField) map[string]bool {
+	opts := map[string]bool{}
+
+	parts := strings.Split(f.Tag.Get("nbt"), ",")
+	for _, opt := range parts[1:] {
+		opts[opt] = true
+	}
+
+	return opts
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
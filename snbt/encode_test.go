@@ -0,0 +1,232 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package snbt
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshal(t *testing.T) {
+	spawnX := 10
+
+	cases := []struct {
+		name     string
+		v        any
+		expected string
+		err      error
+	}{
+		{
+			name:     "positive case: byte",
+			v:        int8(1),
+			expected: "1b",
+		},
+		{
+			name:     "positive case: bool",
+			v:        true,
+			expected: "1b",
+		},
+		{
+			name:     "positive case: short",
+			v:        int16(-2),
+			expected: "-2s",
+		},
+		{
+			name:     "positive case: int",
+			v:        3,
+			expected: "3",
+		},
+		{
+			name:     "positive case: long",
+			v:        int64(4),
+			expected: "4L",
+		},
+		{
+			name:     "positive case: float",
+			v:        float32(20),
+			expected: "20.0f",
+		},
+		{
+			name:     "positive case: double",
+			v:        0.1,
+			expected: "0.1d",
+		},
+		{
+			name:     "positive case: large double",
+			v:        1e300,
+			expected: "1e+300d",
+		},
+		{
+			name:     "positive case: plain string",
+			v:        "stone",
+			expected: "stone",
+		},
+		{
+			name:     "positive case: quoted string",
+			v:        `say "hi"`,
+			expected: `"say \"hi\""`,
+		},
+		{
+			name:     "positive case: numeric string",
+			v:        "1b",
+			expected: `"1b"`,
+		},
+		{
+			name:     "positive case: boolean string",
+			v:        "true",
+			expected: `"true"`,
+		},
+		{
+			name:     "positive case: list",
+			v:        []float64{0.5, 64, 0.5},
+			expected: "[0.5d, 64.0d, 0.5d]",
+		},
+		{
+			name:     "positive case: byte array",
+			v:        []byte{1, 2},
+			expected: "[B; 1b, 2b]",
+		},
+		{
+			name:     "positive case: int array",
+			v:        [4]int32{1, 2, 3, 4},
+			expected: "[I; 1, 2, 3, 4]",
+		},
+		{
+			name:     "positive case: empty long array",
+			v:        []int64(nil),
+			expected: "[L;]",
+		},
+		{
+			name:     "positive case: map",
+			v:        map[string]any{"b": int8(1), "a key": "x", "nil": nil},
+			expected: `{"a key": x, b: 1b}`,
+		},
+		{
+			name: "positive case: struct",
+			v: struct {
+				Pos       [3]float64 `nbt:"Pos"`
+				UUID      string     `nbt:"UUID,uuid"`
+				NoAI      bool       `nbt:"NoAI,omitempty"`
+				Silent    bool       `nbt:"Silent,omitempty"`
+				SpawnX    *int       `nbt:"SpawnX"`
+				SpawnY    *int       `nbt:"SpawnY"`
+				CustomTag string
+				Ignored   string `nbt:"-"`
+			}{
+				Pos:       [3]float64{0, 64, 0},
+				UUID:      "853c80ef-3c37-49fd-aa49-938b674adae6",
+				Silent:    true,
+				SpawnX:    &spawnX,
+				CustomTag: "a b",
+				Ignored:   "x",
+			},
+			expected: `{CustomTag: "a b", Pos: [0.0d, 64.0d, 0.0d], Silent: 1b, SpawnX: 10, UUID: [I; -2059632401, 1010256381, -1438018677, 1732958950]}`,
+		},
+		{
+			name: "negative case: out of range",
+			v:    int(math.MaxInt32 + 1),
+			err:  &UnsupportedValueError{},
+		},
+		{
+			name: "negative case: unsigned out of range",
+			v:    uint8(200),
+			err:  &UnsupportedValueError{},
+		},
+		{
+			name: "negative case: NaN",
+			v:    math.NaN(),
+			err:  &UnsupportedValueError{},
+		},
+		{
+			name: "negative case: invalid uuid",
+			v: struct {
+				UUID string `nbt:"UUID,uuid"`
+			}{UUID: "x"},
+			err: &UnsupportedValueError{},
+		},
+		{
+			name: "negative case: unsupported type",
+			v:    make(chan int),
+			err:  &UnsupportedTypeError{},
+		},
+		{
+			name: "negative case: non-string key",
+			v:    map[int]int{1: 1},
+			err:  &UnsupportedTypeError{},
+		},
+		{
+			name: "negative case: nil",
+			v:    nil,
+			err:  &UnsupportedValueError{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Marshal(tt.v)
+
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, actual)
+			} else {
+				assert.IsType(t, tt.err, err)
+			}
+		})
+	}
+}
+
+func TestMarshal_roundTrip(t *testing.T) {
+	tag, err := Parse(playerSNBT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Marshal(tag)
+	assert.NoError(t, err)
+
+	actual, err := Parse(s)
+	assert.NoError(t, err)
+	assert.Equal(t, tag, actual)
+
+	var p player
+	if err := Unmarshal(playerSNBT, &p); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = Marshal(p)
+	assert.NoError(t, err)
+
+	var q player
+	assert.NoError(t, Unmarshal(s, &q))
+	assert.Equal(t, p, q)
+}
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, "minecraft_stone", Quote("minecraft_stone"))
+	assert.Equal(t, `"minecraft:stone"`, Quote("minecraft:stone"))
+	assert.Equal(t, `"\\"`, Quote(`\`))
+	assert.Equal(t, `""`, Quote(""))
+	assert.Equal(t, `"12"`, Quote("12"))
+}
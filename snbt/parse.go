@@ -0,0 +1,433 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package snbt parses and decodes stringified NBT, the text format of NBT
+// used by commands such as `/data get`.
+//
+// Tags are parsed into Go values:
+//
+//	Byte       int8
+//	Short      int16
+//	Int        int32
+//	Long       int64
+//	Float      float32
+//	Double     float64
+//	String     string
+//	List       []any
+//	Compound   map[string]any
+//	Byte Array []int8
+//	Int Array  []int32
+//	Long Array []int64
+package snbt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SyntaxError is returned when the input is not valid SNBT.
+type SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("snbt: %s at offset %d", e.Msg, e.Offset)
+}
+
+// Parse parses s into Go values.
+func Parse(s string) (any, error) {
+	p := &parser{s: s}
+
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		return nil, p.errorf("trailing data")
+	}
+
+	return v, nil
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return &SyntaxError{Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *parser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+
+	return 0
+}
+
+func (p *parser) expect(c byte) error {
+	p.skipSpace()
+	if p.peek() != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+
+	return nil
+}
+
+func (p *parser) value() (any, error) {
+	p.skipSpace()
+
+	switch c := p.peek(); {
+	case c == '{':
+		return p.compound()
+	case c == '[':
+		return p.list()
+	case c == '"' || c == '\'':
+		return p.quoted()
+	case isUnquoted(c):
+		return p.scalar(p.unquoted()), nil
+	case c == 0:
+		return nil, p.errorf("unexpected end of input")
+	default:
+		return nil, p.errorf("unexpected %q", c)
+	}
+}
+
+func (p *parser) compound() (map[string]any, error) {
+	p.pos++
+
+	m := map[string]any{}
+
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.pos++
+		return m, nil
+	}
+
+	for {
+		p.skipSpace()
+
+		var key string
+		switch c := p.peek(); {
+		case c == '"' || c == '\'':
+			k, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			key = k
+		case isUnquoted(c):
+			key = p.unquoted()
+		default:
+			return nil, p.errorf("expected key")
+		}
+
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return m, nil
+		default:
+			return nil, p.errorf("expected ',' or '}'")
+		}
+	}
+}
+
+func (p *parser) list() (any, error) {
+	p.pos++
+
+	// NOTE: typed arrays start with `B;`, `I;` or `L;`
+	if p.pos+1 < len(p.s) && p.s[p.pos+1] == ';' {
+		switch p.s[p.pos] {
+		case 'B', 'I', 'L':
+			typ := p.s[p.pos]
+			p.pos += 2
+			return p.array(typ)
+		}
+	}
+
+	l := []any{}
+
+	p.skipSpace()
+	if p.peek() == ']' {
+		p.pos++
+		return l, nil
+	}
+
+	for {
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, v)
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return l, nil
+		default:
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+func (p *parser) array(typ byte) (any, error) {
+	elems := []int64{}
+
+	p.skipSpace()
+	if p.peek() == ']' {
+		p.pos++
+		return typedArray(typ, elems), nil
+	}
+
+	for {
+		p.skipSpace()
+		start := p.pos
+
+		var (
+			n  int64
+			ok bool
+		)
+		switch v := p.scalar(p.unquoted()).(type) {
+		case int8:
+			n, ok = int64(v), typ == 'B'
+		case int32:
+			n, ok = int64(v), typ == 'I'
+		case int64:
+			n, ok = v, typ == 'L'
+		}
+		if !ok {
+			p.pos = start
+			return nil, p.errorf("invalid element of [%c;] array", typ)
+		}
+		elems = append(elems, n)
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return typedArray(typ, elems), nil
+		default:
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+func typedArray(typ byte, elems []int64) any {
+	switch typ {
+	case 'B':
+		a := make([]int8, len(elems))
+		for i, n := range elems {
+			a[i] = int8(n)
+		}
+		return a
+	case 'I':
+		a := make([]int32, len(elems))
+		for i, n := range elems {
+			a[i] = int32(n)
+		}
+		return a
+	default:
+		return elems
+	}
+}
+
+func (p *parser) quoted() (string, error) {
+	q := p.s[p.pos]
+	p.pos++
+
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+
+		switch c {
+		case q:
+			return b.String(), nil
+		case '\\':
+			if p.pos >= len(p.s) {
+				return "", p.errorf("unterminated string")
+			}
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *parser) unquoted() string {
+	start := p.pos
+	for p.pos < len(p.s) && isUnquoted(p.s[p.pos]) {
+		p.pos++
+	}
+
+	return p.s[start:p.pos]
+}
+
+// scalar converts an unquoted token into a number, or a boolean as a byte,
+// falling back to a string like the game does.
+func (p *parser) scalar(s string) any {
+	switch s {
+	case "true":
+		return int8(1)
+	case "false":
+		return int8(0)
+	}
+
+	if v, ok := parseNumber(s); ok {
+		return v
+	}
+
+	return s
+}
+
+func parseNumber(s string) (any, bool) {
+	if s == "" {
+		return nil, false
+	}
+
+	body, suffix := s[:len(s)-1], s[len(s)-1]
+	switch suffix {
+	case 'b', 'B':
+		if n, err := strconv.ParseInt(body, 10, 8); err == nil && isInteger(body) {
+			return int8(n), true
+		}
+	case 's', 'S':
+		if n, err := strconv.ParseInt(body, 10, 16); err == nil && isInteger(body) {
+			return int16(n), true
+		}
+	case 'l', 'L':
+		if n, err := strconv.ParseInt(body, 10, 64); err == nil && isInteger(body) {
+			return n, true
+		}
+	case 'f', 'F':
+		if f, err := strconv.ParseFloat(body, 32); err == nil && isDecimal(body) {
+			return float32(f), true
+		}
+	case 'd', 'D':
+		if f, err := strconv.ParseFloat(body, 64); err == nil && isDecimal(body) {
+			return f, true
+		}
+	}
+
+	if isInteger(s) {
+		if n, err := strconv.ParseInt(s, 10, 32); err == nil {
+			return int32(n), true
+		}
+		return nil, false
+	}
+
+	if isDecimal(s) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, true
+		}
+	}
+
+	return nil, false
+}
+
+// isInteger reports whether s is a signed decimal integer.
+func isInteger(s string) bool {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isDecimal reports whether s is a signed decimal number, with an optional
+// fraction and exponent.
+func isDecimal(s string) bool {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	digits := 0
+	i := 0
+	for ; i < len(s) && '0' <= s[i] && s[i] <= '9'; i++ {
+		digits++
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		for ; i < len(s) && '0' <= s[i] && s[i] <= '9'; i++ {
+			digits++
+		}
+	}
+	if digits == 0 {
+		return false
+	}
+
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '-' || s[i] == '+') {
+			i++
+		}
+		if !isInteger(s[i:]) {
+			return false
+		}
+		return true
+	}
+
+	return i == len(s)
+}
+
+func isUnquoted(c byte) bool {
+	return '0' <= c && c <= '9' || 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || c == '_' || c == '-' || c == '.' || c == '+'
+}
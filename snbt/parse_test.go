@@ -0,0 +1,190 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package snbt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name     string
+		s        string
+		expected any
+		err      error
+	}{
+		{
+			name:     "positive case: byte",
+			s:        "1b",
+			expected: int8(1),
+		},
+		{
+			name:     "positive case: boolean",
+			s:        "true",
+			expected: int8(1),
+		},
+		{
+			name:     "positive case: short",
+			s:        "-2s",
+			expected: int16(-2),
+		},
+		{
+			name:     "positive case: int",
+			s:        "3",
+			expected: int32(3),
+		},
+		{
+			name:     "positive case: long",
+			s:        "4L",
+			expected: int64(4),
+		},
+		{
+			name:     "positive case: float",
+			s:        "0.5f",
+			expected: float32(0.5),
+		},
+		{
+			name:     "positive case: double",
+			s:        "1.5d",
+			expected: 1.5,
+		},
+		{
+			name:     "positive case: double without suffix",
+			s:        "-1.5e2",
+			expected: -150.0,
+		},
+		{
+			name:     "positive case: unquoted string",
+			s:        "stone",
+			expected: "stone",
+		},
+		{
+			name:     "positive case: out of range int as string",
+			s:        "3000000000",
+			expected: "3000000000",
+		},
+		{
+			name:     "positive case: out of range byte as string",
+			s:        "128b",
+			expected: "128b",
+		},
+		{
+			name:     "positive case: double quoted string",
+			s:        `"a \"b\" \\c"`,
+			expected: `a "b" \c`,
+		},
+		{
+			name:     "positive case: single quoted string",
+			s:        `'{"text":"hi"}'`,
+			expected: `{"text":"hi"}`,
+		},
+		{
+			name:     "positive case: list",
+			s:        "[0.5d, 64.0d, 0.5d]",
+			expected: []any{0.5, 64.0, 0.5},
+		},
+		{
+			name:     "positive case: empty list",
+			s:        "[]",
+			expected: []any{},
+		},
+		{
+			name:     "positive case: byte array",
+			s:        "[B; 1b, -1b]",
+			expected: []int8{1, -1},
+		},
+		{
+			name:     "positive case: int array",
+			s:        "[I; 1, 2, 3, 4]",
+			expected: []int32{1, 2, 3, 4},
+		},
+		{
+			name:     "positive case: empty long array",
+			s:        "[L;]",
+			expected: []int64{},
+		},
+		{
+			name: "positive case: compound",
+			s:    `{Health: 20.0f, "custom key": {}, Tags: ["a", b]}`,
+			expected: map[string]any{
+				"Health":     float32(20),
+				"custom key": map[string]any{},
+				"Tags":       []any{"a", "b"},
+			},
+		},
+		{
+			name: "negative case: unterminated compound",
+			s:    "{a: 1",
+			err:  &SyntaxError{},
+		},
+		{
+			name: "negative case: missing colon",
+			s:    "{a 1}",
+			err:  &SyntaxError{},
+		},
+		{
+			name: "negative case: unterminated string",
+			s:    `"abc`,
+			err:  &SyntaxError{},
+		},
+		{
+			name: "negative case: invalid array element",
+			s:    "[I; 1b]",
+			err:  &SyntaxError{},
+		},
+		{
+			name: "negative case: trailing data",
+			s:    "1b 2b",
+			err:  &SyntaxError{},
+		},
+		{
+			name: "negative case: empty",
+			s:    "",
+			err:  &SyntaxError{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Parse(tt.s)
+
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, actual)
+			} else {
+				assert.Error(t, err)
+				assert.IsType(t, tt.err, err)
+			}
+		})
+	}
+}
+
+func TestSyntaxError_Error(t *testing.T) {
+	_, err := Parse("{a 1}")
+	assert.EqualError(t, err, `snbt: expected ':' at offset 3`)
+
+	var nilErr *SyntaxError
+	assert.Equal(t, "<nil>", nilErr.Error())
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package rcon
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"time"
+	"unicode/utf8"
+
+	liblog "github.com/Aton-Kish/gorcon/log"
+	"github.com/Aton-Kish/gorcon/protocol"
+)
+
+// Streamer is implemented by connections that stream responses, such as
+// connections returned by Dial.
+type Streamer interface {
+	// CommandStream sends the command and streams its response packet by
+	// packet. The caller must close the stream.
+	CommandStream(command string) (io.ReadCloser, error)
+}
+
+var _ Streamer = (*rcon)(nil)
+
+// CommandStream sends the command and returns its response as a stream that
+// yields data packet by packet instead of buffering the whole response. Reads
+// never end within a UTF-8 character, even if the server split it across
+// packets.
+//
+// The connection is reserved for the stream until it is read to EOF or
+// closed, so the caller must always call Close. The stream bypasses the
+// middleware chain.
+func (c *rcon) CommandStream(command string) (io.ReadCloser, error) {
+	ctx := context.Background()
+
+	c.mu.Lock()
+
+	s := &commandStream{c: c, ctx: ctx, command: command, start: time.Now(), id: rand.Int31()}
+	s.stop = c.watch(ctx)
+
+	if err := c.validate(command); err != nil {
+		return nil, s.fail(err)
+	}
+
+	req := protocol.NewPacket(s.id, protocol.CommandRequestType, []byte(command))
+	if err := c.encode(ctx, req); err != nil {
+		return nil, s.fail(err)
+	}
+
+	res := new(protocol.Packet)
+	if err := c.decode(ctx, res, false); err != nil {
+		return nil, s.fail(err)
+	}
+
+	if res.Length() >= maxResponseLength {
+		// NOTE: dummy request
+		dummy := protocol.NewPacket(s.id, protocol.DummyRequestType, []byte{})
+		if err := c.encode(ctx, dummy); err != nil {
+			return nil, s.fail(err)
+		}
+
+		s.more = true
+	}
+
+	s.feed(res.Payload)
+
+	return s, nil
+}
+
+type commandStream struct {
+	c       *rcon
+	ctx     context.Context
+	command string
+	start   time.Time
+	id      int32
+	stop    func(err error)
+
+	buf           []byte
+	pending       []byte
+	size          int
+	continuations int
+	more          bool
+	err           error
+	released      bool
+}
+
+func (s *commandStream) Read(p []byte) (int, error) {
+	for len(s.buf) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+
+		s.next()
+	}
+
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+
+	return n, nil
+}
+
+// Close drains the rest of the response, so that the connection stays in
+// sync, and releases the connection.
+func (s *commandStream) Close() error {
+	for s.err == nil {
+		s.buf = nil
+		s.next()
+	}
+
+	if s.err == io.EOF {
+		return nil
+	}
+
+	return s.err
+}
+
+// next reads the next packet, or ends the stream.
+func (s *commandStream) next() {
+	if !s.more {
+		// NOTE: flush what is left, even if it is not valid UTF-8
+		s.buf = append(s.buf, s.pending...)
+		s.pending = nil
+		s.err = io.EOF
+		s.release(nil)
+		return
+	}
+
+	p := new(protocol.Packet)
+	if err := s.c.decode(s.ctx, p, true); err != nil {
+		s.err = s.fail(err)
+		return
+	}
+
+	if string(p.Payload) == "Unknown request 64" {
+		// NOTE: termination
+		s.more = false
+		return
+	}
+
+	s.continuations++
+	s.feed(p.Payload)
+}
+
+// feed appends a payload, holding back a trailing incomplete UTF-8 character
+// until the next packet completes it.
+func (s *commandStream) feed(payload []byte) {
+	s.size += len(payload)
+
+	data := append(s.pending, payload...)
+	i := incompleteRuneStart(data)
+
+	s.buf = append(s.buf, data[:i]...)
+	s.pending = append([]byte(nil), data[i:]...)
+}
+
+func (s *commandStream) fail(err error) error {
+	if ctxErr := s.ctx.Err(); ctxErr != nil {
+		err = ctxErr
+	}
+
+	err = &RCONError{Op: "command", Err: err}
+	s.release(err)
+
+	return err
+}
+
+// release records the outcome and unlocks the connection, exactly once.
+func (s *commandStream) release(err error) {
+	if s.released {
+		return
+	}
+	s.released = true
+
+	c := s.c
+	if err != nil {
+		c.stats.failures.Add(1)
+		c.hooks.CommandDone(c.addr, time.Since(s.start), 0, s.continuations, err)
+		c.getLogger(s.ctx).Log(liblog.LevelError, "failed to stream", liblog.String("func", getFuncName()), liblog.String("command", c.redact(s.command)), liblog.Err(err))
+	} else {
+		c.stats.commands.Add(1)
+		c.hooks.CommandDone(c.addr, time.Since(s.start), s.size, s.continuations, nil)
+	}
+
+	s.stop(err)
+	c.mu.Unlock()
+}
+
+// incompleteRuneStart returns the index of a trailing incomplete UTF-8
+// character in b, or len(b) if there is none.
+func incompleteRuneStart(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(b[i]) {
+			continue
+		}
+
+		if utf8.FullRune(b[i:]) {
+			return len(b)
+		}
+
+		return i
+	}
+
+	return len(b)
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package rcon
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Aton-Kish/gorcon/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveSplit answers one command with the given payloads as separate packets.
+func serveSplit(srv *rcon, payloads ...string) {
+	req, err := srv.ReadPacket()
+	if err != nil {
+		return
+	}
+
+	for i, payload := range payloads {
+		srv.SendPacket(protocol.NewPacket(req.RequestId, protocol.CommandResponseType, []byte(payload)))
+
+		// NOTE: the client sends the dummy request after the first packet
+		if i == 0 && len(payloads) > 1 {
+			if _, err := srv.ReadPacket(); err != nil {
+				return
+			}
+		}
+	}
+
+	if len(payloads) > 1 {
+		srv.SendPacket(protocol.NewPacket(req.RequestId, protocol.CommandResponseType, []byte("Unknown request 64")))
+	}
+}
+
+func Test_rcon_CommandStream(t *testing.T) {
+	// NOTE: "あ" is encoded in 3 bytes
+	head := strings.Repeat("a", maxResponsePayloadSize-2) + "あ"[:2]
+	tail := "あ"[2:] + "bc"
+
+	cases := []struct {
+		name     string
+		payloads []string
+		expected []string
+	}{
+		{
+			name:     "positive case: single packet",
+			payloads: []string{"Seed: [1]"},
+			expected: []string{"Seed: [1]"},
+		},
+		{
+			name:     "positive case: split response",
+			payloads: []string{strings.Repeat("a", maxResponsePayloadSize), "b"},
+			expected: []string{strings.Repeat("a", maxResponsePayloadSize), "b"},
+		},
+		{
+			name:     "positive case: split UTF-8 character",
+			payloads: []string{head, tail},
+			expected: []string{strings.Repeat("a", maxResponsePayloadSize-2), "あbc"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, clt := pipe()
+			defer srv.Close()
+			defer clt.Close()
+
+			go serveSplit(srv, tt.payloads...)
+
+			s, err := clt.CommandStream("/seed")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			chunks := []string{}
+			buf := make([]byte, 2*maxResponsePayloadSize)
+			for {
+				n, err := s.Read(buf)
+				if err == io.EOF {
+					break
+				}
+				assert.NoError(t, err)
+				assert.True(t, utf8.Valid(buf[:n]))
+				chunks = append(chunks, string(buf[:n]))
+			}
+
+			assert.Equal(t, tt.expected, chunks)
+			assert.NoError(t, s.Close())
+			assert.Equal(t, uint64(1), clt.Stats().Commands)
+		})
+	}
+}
+
+func Test_rcon_CommandStream_Close(t *testing.T) {
+	srv, clt := pipe()
+	defer srv.Close()
+	defer clt.Close()
+
+	go func() {
+		serveSplit(srv, strings.Repeat("a", maxResponsePayloadSize), "b")
+		serveSplit(srv, "Seed: [1]")
+	}()
+
+	s, err := clt.CommandStream("/help")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 8)
+	_, err = s.Read(buf)
+	assert.NoError(t, err)
+
+	// NOTE: Close drains the rest of the response
+	assert.NoError(t, s.Close())
+
+	res, err := clt.Command("/seed")
+	assert.NoError(t, err)
+	assert.Equal(t, "Seed: [1]", res)
+}
+
+func Test_rcon_CommandStream_Error(t *testing.T) {
+	srv, clt := pipe()
+	defer clt.Close()
+	srv.Close()
+
+	s, err := clt.CommandStream("/seed")
+	assert.Nil(t, s)
+	assert.IsType(t, &RCONError{}, err)
+	assert.Equal(t, Stats{Failures: 1}, clt.Stats())
+}
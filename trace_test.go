@@ -0,0 +1,162 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package rcon
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Aton-Kish/gorcon/protocol"
+	"github.com/Aton-Kish/gorcon/rcontrace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialContext_trace(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	errCh := make(chan error, 1)
+	defer close(errCh)
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			errCh <- err
+			return
+		}
+		defer conn.Close()
+
+		enc, dec := protocol.NewEncoder(conn), protocol.NewDecoder(conn)
+
+		req := new(protocol.Packet)
+		if err := dec.Decode(req); err != nil {
+			errCh <- err
+			return
+		}
+
+		if err := enc.Encode(protocol.NewPacket(req.RequestId, protocol.AuthResponseType, []byte{})); err != nil {
+			errCh <- err
+			return
+		}
+
+		if err := dec.Decode(req); err != nil {
+			errCh <- err
+			return
+		}
+
+		responses := []*protocol.Packet{
+			protocol.NewPacket(req.RequestId, protocol.CommandResponseType, []byte(strings.Repeat("r", maxResponsePayloadSize))),
+			protocol.NewPacket(req.RequestId, protocol.CommandResponseType, []byte("esponse")),
+			protocol.NewPacket(req.RequestId, protocol.CommandResponseType, []byte("Unknown request 64")),
+		}
+
+		if err := enc.Encode(responses[0]); err != nil {
+			errCh <- err
+			return
+		}
+
+		if err := dec.Decode(req); err != nil {
+			errCh <- err
+			return
+		}
+
+		for _, res := range responses[1:] {
+			if err := enc.Encode(res); err != nil {
+				errCh <- err
+				return
+			}
+		}
+
+		errCh <- nil
+	}()
+
+	var mu sync.Mutex
+	events := []string{}
+	record := func(format string, a ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		events = append(events, fmt.Sprintf(format, a...))
+	}
+
+	trace := &rcontrace.ClientTrace{
+		DNSStart:      func(info rcontrace.DNSStartInfo) { record("DNSStart %s", info.Host) },
+		DNSDone:       func(info rcontrace.DNSDoneInfo) { record("DNSDone %v", info.Err) },
+		ConnectStart:  func(network string, addr string) { record("ConnectStart %s", network) },
+		ConnectDone:   func(network string, addr string, err error) { record("ConnectDone %s %v", network, err) },
+		AuthSent:      func() { record("AuthSent") },
+		AuthDone:      func(err error) { record("AuthDone %v", err) },
+		PacketWritten: func(info rcontrace.PacketInfo) { record("PacketWritten %d", info.PacketType) },
+		PacketRead: func(info rcontrace.PacketInfo) {
+			record("PacketRead %d %d %t", info.PacketType, info.Length, info.Continuation)
+		},
+		CommandStart: func(info rcontrace.CommandStartInfo) { record("CommandStart %s", info.Command) },
+		CommandDone: func(info rcontrace.CommandDoneInfo) {
+			record("CommandDone %s %d %d %v", info.Command, info.Size, info.Continuations, info.Err)
+		},
+	}
+	ctx := rcontrace.WithClientTrace(context.Background(), trace)
+
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	conn, err := DialContext(ctx, net.JoinHostPort("localhost", port), mockPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	res, err := AsClient(conn).CommandContext(ctx, "request")
+	assert.NoError(t, err)
+	assert.Len(t, res, maxResponsePayloadSize+len("esponse"))
+	assert.NoError(t, <-errCh)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// NOTE: localhost may resolve to several addresses
+	assert.Equal(t, "DNSStart localhost", events[0])
+	assert.Equal(t, "DNSDone <nil>", events[1])
+
+	expected := []string{
+		"ConnectStart tcp",
+		"ConnectDone tcp <nil>",
+		"PacketWritten 3",
+		"AuthSent",
+		"PacketRead 2 10 false",
+		"AuthDone <nil>",
+		"CommandStart request",
+		"PacketWritten 2",
+		"PacketRead 0 4106 false",
+		"PacketWritten 100",
+		"PacketRead 0 17 true",
+		"PacketRead 0 28 true",
+		"CommandDone request 4103 1 <nil>",
+	}
+	assert.Equal(t, expected, events[len(events)-len(expected):])
+}
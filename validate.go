@@ -0,0 +1,59 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package rcon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Aton-Kish/gorcon/protocol"
+)
+
+var (
+	ErrPayloadTooLarge = errors.New("payload too large")
+	ErrInvalidCommand  = errors.New("invalid command")
+)
+
+// DefaultMaxCommandSize is the default limit of WithMaxCommandSize, that of
+// Minecraft. Source servers accept up to protocol.ServerdataMaxPayloadSize.
+const DefaultMaxCommandSize = protocol.MaxRequestPayloadSize
+
+// validate checks the command before it is sent, since the server drops the
+// connection on a request it cannot handle.
+func (c *rcon) validate(command string) error {
+	if c.maxCommandSize > 0 && len(command) > c.maxCommandSize {
+		return fmt.Errorf("%w: %d bytes exceeds the limit of %d", ErrPayloadTooLarge, len(command), c.maxCommandSize)
+	}
+
+	// NOTE: a NUL byte terminates the payload and breaks the framing
+	if i := strings.IndexByte(command, 0); i >= 0 {
+		return fmt.Errorf("%w: NUL byte at %d", ErrInvalidCommand, i)
+	}
+
+	return nil
+}
+
+// isInvalidRequest reports whether err was caused by the request itself
+// rather than by the server.
+func isInvalidRequest(err error) bool {
+	return errors.Is(err, ErrPayloadTooLarge) || errors.Is(err, ErrInvalidCommand)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2022 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !e2e
+
+package rcon
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/gorcon/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_rcon_validate(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     []Option
+		command  string
+		expected error
+	}{
+		{
+			name:     "positive case",
+			command:  "/seed",
+			expected: nil,
+		},
+		{
+			name:     "positive case: limit",
+			command:  strings.Repeat("a", protocol.MaxRequestPayloadSize),
+			expected: nil,
+		},
+		{
+			name:     "positive case: custom limit",
+			opts:     []Option{WithMaxCommandSize(protocol.ServerdataMaxPayloadSize)},
+			command:  strings.Repeat("a", protocol.ServerdataMaxPayloadSize),
+			expected: nil,
+		},
+		{
+			name:     "positive case: no limit",
+			opts:     []Option{WithMaxCommandSize(0)},
+			command:  strings.Repeat("a", 10000),
+			expected: nil,
+		},
+		{
+			name:     "negative case: too large",
+			command:  strings.Repeat("a", protocol.MaxRequestPayloadSize+1),
+			expected: ErrPayloadTooLarge,
+		},
+		{
+			name:     "negative case: too large for custom limit",
+			opts:     []Option{WithMaxCommandSize(4)},
+			command:  "/seed",
+			expected: ErrPayloadTooLarge,
+		},
+		{
+			name:     "negative case: NUL byte",
+			command:  "/say a\x00b",
+			expected: ErrInvalidCommand,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, clt := pipe(tt.opts...)
+			defer clt.Close()
+
+			err := clt.validate(tt.command)
+
+			if tt.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_rcon_Command_invalid(t *testing.T) {
+	srv, clt := pipe()
+	defer srv.Close()
+	defer clt.Close()
+
+	// NOTE: nothing is written, so the pipe would block if the command were sent
+	_, err := clt.Command(strings.Repeat("a", protocol.MaxRequestPayloadSize+1))
+	assert.IsType(t, &RCONError{}, err)
+	assert.ErrorIs(t, err, ErrPayloadTooLarge)
+
+	_, err = clt.CommandStream("/say \x00")
+	assert.IsType(t, &RCONError{}, err)
+	assert.ErrorIs(t, err, ErrInvalidCommand)
+
+	assert.Equal(t, Stats{Failures: 2}, clt.Stats())
+}
+
+func Test_rcon_commandBatch_invalid(t *testing.T) {
+	s := newMockServer(t, 0)
+	defer s.close()
+
+	conn, err := Dial(s.addr(), mockPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	results := NewBatch("/seed", "/say \x00", "/list").Run(context.Background(), AsClient(conn))
+
+	assert.Len(t, results, 3)
+	assert.Equal(t, Result{Command: "/seed", Response: "/seed"}, results[0])
+	assert.ErrorIs(t, results[1].Err, ErrInvalidCommand)
+	assert.Equal(t, Result{Command: "/list", Response: "/list"}, results[2])
+}
+
+func TestBreaker_invalidCommand(t *testing.T) {
+	b := NewBreaker(BreakerConfig{Threshold: 1})
+
+	srv, clt := pipe(WithMiddleware(b.Middleware()))
+	defer srv.Close()
+	defer clt.Close()
+
+	_, err := clt.Command("/say \x00")
+	assert.ErrorIs(t, err, ErrInvalidCommand)
+	assert.Equal(t, BreakerClosed, b.State())
+}